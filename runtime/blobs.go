@@ -0,0 +1,98 @@
+package runtime
+
+import "fmt"
+
+// BlobConfig are the blob params of a fork (eip-4844)
+type BlobConfig struct {
+	// Target is the target number of blobs per block
+	Target uint64 `json:"target"`
+
+	// Max is the maximum number of blobs per block
+	Max uint64 `json:"max"`
+
+	// UpdateFraction is the update fraction of the blob base fee
+	UpdateFraction uint64 `json:"baseFeeUpdateFraction"`
+}
+
+var (
+	// CancunBlobConfig are the blob params introduced in cancun (eip-4844)
+	CancunBlobConfig = BlobConfig{Target: 3, Max: 6, UpdateFraction: 3338477}
+
+	// PragueBlobConfig are the blob params increased in prague (eip-7691)
+	PragueBlobConfig = BlobConfig{Target: 6, Max: 9, UpdateFraction: 5007716}
+)
+
+// GetBlobConfig returns the blob params of the chain. If not set, the
+// params of the latest enabled blob eip are used.
+func (f *ForksInTime) GetBlobConfig() BlobConfig {
+	if f.Blobs != nil {
+		return *f.Blobs
+	}
+	if f.Enabled(EIP7691) {
+		return PragueBlobConfig
+	}
+	return CancunBlobConfig
+}
+
+// BlobSchedule are the blob params of the forks that change them. The
+// blob parameter only forks (eip-7892) do not have default params.
+type BlobSchedule struct {
+	Cancun *BlobConfig `json:"cancun,omitempty"`
+	Prague *BlobConfig `json:"prague,omitempty"`
+	Osaka  *BlobConfig `json:"osaka,omitempty"`
+	BPO1   *BlobConfig `json:"bpo1,omitempty"`
+	BPO2   *BlobConfig `json:"bpo2,omitempty"`
+	BPO3   *BlobConfig `json:"bpo3,omitempty"`
+	BPO4   *BlobConfig `json:"bpo4,omitempty"`
+	BPO5   *BlobConfig `json:"bpo5,omitempty"`
+}
+
+// At returns the blob params of the latest enabled fork of the schedule
+// or nil if none of the enabled forks is in the schedule
+func (s *BlobSchedule) At(forks *ForksInTime) *BlobConfig {
+	schedule := []struct {
+		enabled bool
+		config  *BlobConfig
+	}{
+		{forks.BPO5, s.BPO5},
+		{forks.BPO4, s.BPO4},
+		{forks.BPO3, s.BPO3},
+		{forks.BPO2, s.BPO2},
+		{forks.BPO1, s.BPO1},
+		{forks.Osaka, s.Osaka},
+		{forks.Prague, s.Prague},
+		{forks.Cancun, s.Cancun},
+	}
+	for _, fork := range schedule {
+		if fork.enabled && fork.config != nil {
+			return fork.config
+		}
+	}
+	return nil
+}
+
+// validate checks that the scheduled blob parameter only forks have
+// blob params
+func (s *BlobSchedule) validate(forks *Forks) error {
+	if s == nil {
+		s = &BlobSchedule{}
+	}
+
+	bpos := []struct {
+		name   string
+		fork   *Fork
+		config *BlobConfig
+	}{
+		{"bpo1", forks.BPO1Time, s.BPO1},
+		{"bpo2", forks.BPO2Time, s.BPO2},
+		{"bpo3", forks.BPO3Time, s.BPO3},
+		{"bpo4", forks.BPO4Time, s.BPO4},
+		{"bpo5", forks.BPO5Time, s.BPO5},
+	}
+	for _, bpo := range bpos {
+		if bpo.fork != nil && bpo.config == nil {
+			return fmt.Errorf("fork %s scheduled without blob params", bpo.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package runtime
+
+import "github.com/0xPolygon/eth-state-transition/types"
+
+// MainnetParams are the params of the ethereum mainnet
+var MainnetParams = &Params{
+	ChainID: 1,
+	Forks: &Forks{
+		Homestead:      NewFork(1150000),
+		EIP150:         NewFork(2463000),
+		EIP155:         NewFork(2675000),
+		EIP158:         NewFork(2675000),
+		Byzantium:      NewFork(4370000),
+		Constantinople: NewFork(7280000),
+		Petersburg:     NewFork(7280000),
+		Istanbul:       NewFork(9069000),
+		Berlin:         NewFork(12244000),
+		London:         NewFork(12965000),
+		Paris:          NewFork(15537394),
+		ShanghaiTime:   NewFork(1681338455),
+		CancunTime:     NewFork(1710338135),
+		PragueTime:     NewFork(1746612311),
+		OsakaTime:      NewFork(1764798551),
+		BPO1Time:       NewFork(1765290071),
+		BPO2Time:       NewFork(1767747671),
+	},
+	DepositContract: addressPtr("0x00000000219ab540356cBB839Cbe05303d7705Fa"),
+	Irregular: []*IrregularChange{
+		NewDAOForkChange(1920000),
+	},
+	BlobSchedule: ethereumBlobSchedule(),
+}
+
+// SepoliaParams are the params of the sepolia testnet
+var SepoliaParams = &Params{
+	ChainID: 11155111,
+	Forks: &Forks{
+		Homestead:      NewFork(0),
+		EIP150:         NewFork(0),
+		EIP155:         NewFork(0),
+		EIP158:         NewFork(0),
+		Byzantium:      NewFork(0),
+		Constantinople: NewFork(0),
+		Petersburg:     NewFork(0),
+		Istanbul:       NewFork(0),
+		Berlin:         NewFork(0),
+		London:         NewFork(0),
+		Paris:          NewFork(1735371),
+		ShanghaiTime:   NewFork(1677557088),
+		CancunTime:     NewFork(1706655072),
+		PragueTime:     NewFork(1741159776),
+		OsakaTime:      NewFork(1760427360),
+		BPO1Time:       NewFork(1761017184),
+		BPO2Time:       NewFork(1761607008),
+	},
+	DepositContract: addressPtr("0x7f02C3E3c98b133055B8B348B2Ac625669Ed295D"),
+	BlobSchedule:    ethereumBlobSchedule(),
+}
+
+// HoodiParams are the params of the hoodi testnet
+var HoodiParams = &Params{
+	ChainID: 560048,
+	Forks: &Forks{
+		Homestead:      NewFork(0),
+		EIP150:         NewFork(0),
+		EIP155:         NewFork(0),
+		EIP158:         NewFork(0),
+		Byzantium:      NewFork(0),
+		Constantinople: NewFork(0),
+		Petersburg:     NewFork(0),
+		Istanbul:       NewFork(0),
+		Berlin:         NewFork(0),
+		London:         NewFork(0),
+		Paris:          NewFork(0),
+		ShanghaiTime:   NewFork(0),
+		CancunTime:     NewFork(0),
+		PragueTime:     NewFork(1742999832),
+		OsakaTime:      NewFork(1761677592),
+		BPO1Time:       NewFork(1762365720),
+		BPO2Time:       NewFork(1762955544),
+	},
+	DepositContract: addressPtr("0x00000000219ab540356cBB839Cbe05303d7705Fa"),
+	BlobSchedule:    ethereumBlobSchedule(),
+}
+
+// HoleskyParams are the params of the holesky testnet
+var HoleskyParams = &Params{
+	ChainID: 17000,
+	Forks: &Forks{
+		Homestead:      NewFork(0),
+		EIP150:         NewFork(0),
+		EIP155:         NewFork(0),
+		EIP158:         NewFork(0),
+		Byzantium:      NewFork(0),
+		Constantinople: NewFork(0),
+		Petersburg:     NewFork(0),
+		Istanbul:       NewFork(0),
+		Berlin:         NewFork(0),
+		London:         NewFork(0),
+		Paris:          NewFork(0),
+		ShanghaiTime:   NewFork(1696000704),
+		CancunTime:     NewFork(1707305664),
+		PragueTime:     NewFork(1740434112),
+		OsakaTime:      NewFork(1759308480),
+		BPO1Time:       NewFork(1759800000),
+		BPO2Time:       NewFork(1760389824),
+	},
+	DepositContract: addressPtr("0x4242424242424242424242424242424242424242"),
+	BlobSchedule:    ethereumBlobSchedule(),
+}
+
+// ethereumBlobSchedule returns the blob params of the forks of the
+// ethereum networks
+func ethereumBlobSchedule() *BlobSchedule {
+	cancun, prague := CancunBlobConfig, PragueBlobConfig
+	return &BlobSchedule{
+		Cancun: &cancun,
+		Prague: &prague,
+		BPO1:   &BlobConfig{Target: 10, Max: 15, UpdateFraction: 8346193},
+		BPO2:   &BlobConfig{Target: 14, Max: 21, UpdateFraction: 11684671},
+	}
+}
+
+// Presets are the params of the known chains by name
+var Presets = map[string]*Params{
+	"mainnet": MainnetParams,
+	"sepolia": SepoliaParams,
+	"holesky": HoleskyParams,
+	"hoodi":   HoodiParams,
+}
+
+func addressPtr(str string) *types.Address {
+	addr := types.StringToAddress(str)
+	return &addr
+}
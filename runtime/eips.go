@@ -0,0 +1,152 @@
+package runtime
+
+import "sort"
+
+// EIP is the number of an Ethereum Improvement Proposal that changes the
+// state transition
+type EIP uint64
+
+const (
+	EIP2    EIP = 2    // homestead contract creation rules
+	EIP7    EIP = 7    // DELEGATECALL
+	EIP140  EIP = 140  // REVERT
+	EIP145  EIP = 145  // bitwise shifting instructions
+	EIP150  EIP = 150  // gas cost changes for io-heavy operations
+	EIP152  EIP = 152  // blake2f precompile
+	EIP155  EIP = 155  // replay protection
+	EIP160  EIP = 160  // EXP cost increase
+	EIP161  EIP = 161  // state trie clearing
+	EIP170  EIP = 170  // contract code size limit
+	EIP196  EIP = 196  // bn256 addition and scalar multiplication precompiles
+	EIP197  EIP = 197  // bn256 pairing precompile
+	EIP198  EIP = 198  // modexp precompile
+	EIP211  EIP = 211  // RETURNDATASIZE and RETURNDATACOPY
+	EIP214  EIP = 214  // STATICCALL
+	EIP658  EIP = 658  // status code in the receipts
+	EIP1014 EIP = 1014 // CREATE2
+	EIP1052 EIP = 1052 // EXTCODEHASH
+	EIP1108 EIP = 1108 // bn256 precompiles repricing
+	EIP1153 EIP = 1153 // transient storage
+	EIP1283 EIP = 1283 // net gas metering for SSTORE (removed in petersburg)
+	EIP1344 EIP = 1344 // CHAINID
+	EIP1559 EIP = 1559 // fee market
+	EIP1884 EIP = 1884 // repricing of trie-size-dependent opcodes and SELFBALANCE
+	EIP2028 EIP = 2028 // calldata gas cost reduction
+	EIP2200 EIP = 2200 // net gas metering for SSTORE
+	EIP2537 EIP = 2537 // bls12-381 precompiles
+	EIP2565 EIP = 2565 // modexp repricing
+	EIP2929 EIP = 2929 // gas cost increases for state access opcodes
+	EIP2930 EIP = 2930 // access lists
+	EIP2935 EIP = 2935 // historical block hashes in state
+	EIP3198 EIP = 3198 // BASEFEE
+	EIP3529 EIP = 3529 // reduction in refunds
+	EIP3541 EIP = 3541 // reject new contracts starting with 0xEF
+	EIP3651 EIP = 3651 // warm coinbase
+	EIP3675 EIP = 3675 // upgrade to proof of stake
+	EIP3855 EIP = 3855 // PUSH0
+	EIP3860 EIP = 3860 // initcode limit and metering
+	EIP4399 EIP = 4399 // PREVRANDAO
+	EIP4788 EIP = 4788 // beacon block root in the evm
+	EIP4844 EIP = 4844 // blob transactions and BLOBHASH
+	EIP4895 EIP = 4895 // beacon chain withdrawals
+	EIP5656 EIP = 5656 // MCOPY
+	EIP6110 EIP = 6110 // validator deposits on chain
+	EIP6780 EIP = 6780 // SELFDESTRUCT only in the same transaction
+	EIP7002 EIP = 7002 // execution layer triggerable withdrawals
+	EIP7251 EIP = 7251 // increase the max effective balance
+	EIP7516 EIP = 7516 // BLOBBASEFEE
+	EIP7623 EIP = 7623 // calldata cost increase
+	EIP7685 EIP = 7685 // execution layer requests
+	EIP7691 EIP = 7691 // blob throughput increase
+	EIP7702 EIP = 7702 // set code transactions
+	EIP7823 EIP = 7823 // upper bounds for modexp
+	EIP7825 EIP = 7825 // transaction gas limit cap
+	EIP7883 EIP = 7883 // modexp gas cost increase
+	EIP7939 EIP = 7939 // CLZ
+	EIP7951 EIP = 7951 // secp256r1 precompile
+
+	// RIP7212 is the secp256r1 precompile of the rollups, enabled in bor
+	// by the napoli fork with half the gas of eip-7951. It is not part of
+	// any fork and is only enabled with the eip overrides.
+	RIP7212 EIP = 7212
+)
+
+// forkEIPs expands a fork into the eips it activates
+type forkEIPs struct {
+	name    string
+	enabled func(f *ForksInTime) bool
+	eips    []EIP
+}
+
+var forkEIPsList = []forkEIPs{
+	{"homestead", func(f *ForksInTime) bool { return f.Homestead }, []EIP{EIP2, EIP7}},
+	{"EIP150", func(f *ForksInTime) bool { return f.EIP150 }, []EIP{EIP150}},
+	{"EIP155", func(f *ForksInTime) bool { return f.EIP155 }, []EIP{EIP155}},
+	{"EIP158", func(f *ForksInTime) bool { return f.EIP158 }, []EIP{EIP160, EIP161, EIP170}},
+	{"byzantium", func(f *ForksInTime) bool { return f.Byzantium }, []EIP{EIP140, EIP196, EIP197, EIP198, EIP211, EIP214, EIP658}},
+	{"constantinople", func(f *ForksInTime) bool { return f.Constantinople }, []EIP{EIP145, EIP1014, EIP1052}},
+	// eip-1283 is part of constantinople but petersburg removes it again
+	{"constantinople", func(f *ForksInTime) bool { return f.Constantinople && !f.Petersburg }, []EIP{EIP1283}},
+	{"istanbul", func(f *ForksInTime) bool { return f.Istanbul }, []EIP{EIP152, EIP1108, EIP1344, EIP1884, EIP2028, EIP2200}},
+	{"berlin", func(f *ForksInTime) bool { return f.Berlin }, []EIP{EIP2565, EIP2929, EIP2930}},
+	{"london", func(f *ForksInTime) bool { return f.London }, []EIP{EIP1559, EIP3198, EIP3529, EIP3541}},
+	{"paris", func(f *ForksInTime) bool { return f.Paris }, []EIP{EIP3675, EIP4399}},
+	{"shanghai", func(f *ForksInTime) bool { return f.Shanghai }, []EIP{EIP3651, EIP3855, EIP3860, EIP4895}},
+	{"cancun", func(f *ForksInTime) bool { return f.Cancun }, []EIP{EIP1153, EIP4788, EIP4844, EIP5656, EIP6780, EIP7516}},
+	{"prague", func(f *ForksInTime) bool { return f.Prague }, []EIP{EIP2537, EIP2935, EIP6110, EIP7002, EIP7251, EIP7623, EIP7685, EIP7691, EIP7702}},
+	{"osaka", func(f *ForksInTime) bool { return f.Osaka }, []EIP{EIP7823, EIP7825, EIP7883, EIP7939, EIP7951}},
+}
+
+// eipFork is the fork that activates each eip
+var eipFork = map[EIP]forkEIPs{}
+
+func init() {
+	for _, fork := range forkEIPsList {
+		for _, eip := range fork.eips {
+			eipFork[eip] = fork
+		}
+	}
+}
+
+// ForkEIPs returns the eips activated by the given fork
+func ForkEIPs(name string) []EIP {
+	eips := []EIP{}
+	for _, fork := range forkEIPsList {
+		if fork.name == name {
+			eips = append(eips, fork.eips...)
+		}
+	}
+	return eips
+}
+
+// Enabled returns whether the eip is active. An override in EIPs takes
+// precedence over the fork that activates the eip.
+func (f *ForksInTime) Enabled(eip EIP) bool {
+	if enabled, ok := f.EIPs[eip]; ok {
+		return enabled
+	}
+	fork, ok := eipFork[eip]
+	if !ok {
+		return false
+	}
+	return fork.enabled(f)
+}
+
+// ActiveEIPs returns the sorted list of the active eips
+func (f *ForksInTime) ActiveEIPs() []EIP {
+	eips := []EIP{}
+	for eip := range eipFork {
+		if f.Enabled(eip) {
+			eips = append(eips, eip)
+		}
+	}
+	for eip, enabled := range f.EIPs {
+		if _, ok := eipFork[eip]; !ok && enabled {
+			eips = append(eips, eip)
+		}
+	}
+	sort.Slice(eips, func(i, j int) bool {
+		return eips[i] < eips[j]
+	})
+	return eips
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkEIPs(t *testing.T) {
+	assert.Equal(t, []EIP{EIP3651, EIP3855, EIP3860, EIP4895}, ForkEIPs("shanghai"))
+	assert.Equal(t, []EIP{EIP145, EIP1014, EIP1052, EIP1283}, ForkEIPs("constantinople"))
+	assert.Empty(t, ForkEIPs("unknown"))
+
+	forks := ForksInTime{Shanghai: true}
+	assert.True(t, forks.Enabled(EIP3855))
+	assert.False(t, forks.Enabled(EIP1153))
+	assert.Equal(t, ForkEIPs("shanghai"), forks.ActiveEIPs())
+
+	// petersburg removes the net gas metering of constantinople
+	forks = ForksInTime{Constantinople: true}
+	assert.True(t, forks.Enabled(EIP1283))
+	forks.Petersburg = true
+	assert.False(t, forks.Enabled(EIP1283))
+}
+
+func TestParamsEIPOverrides(t *testing.T) {
+	var p Params
+	data := `{"forks": {"homestead": 0, "london": 0}, "eips": {"3855": 10, "3529": null}}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	// push0 is enabled from block 10 without the rest of shanghai
+	forks := p.At(9, 0)
+	assert.False(t, forks.Enabled(EIP3855))
+
+	forks = p.At(10, 0)
+	assert.True(t, forks.Enabled(EIP3855))
+	assert.False(t, forks.Enabled(EIP3651))
+	assert.False(t, forks.Shanghai)
+
+	// the refund reduction of london is disabled
+	assert.True(t, forks.Enabled(EIP1559))
+	assert.False(t, forks.Enabled(EIP3529))
+
+	// the eips can be overridden by timestamp
+	data = `{"forks": {"homestead": 0}, "eips": {"3855": 10}, "eipsTime": {"5656": 1000, "1153": null}}`
+	p = Params{}
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+	assert.NoError(t, p.Validate())
+
+	forks = p.At(10, 999)
+	assert.True(t, forks.Enabled(EIP3855))
+	assert.False(t, forks.Enabled(EIP5656))
+	assert.False(t, forks.Enabled(EIP1153))
+
+	forks = p.At(10, 1000)
+	assert.True(t, forks.Enabled(EIP5656))
+
+	// but not both by block and by timestamp
+	p.EIPsTime[EIP3855] = NewFork(1000)
+	assert.Error(t, p.Validate())
+}
@@ -37,6 +37,8 @@ func (e *EVM) Run(c *runtime.Contract, host runtime.Host, config *runtime.ForksI
 	contract.gas = c.Gas
 	contract.host = host
 	contract.config = config
+	contract.instructions = instructionSetFor(config)
+	contract.limits = config.GetLimits()
 
 	contract.bitmap.setCode(c.Code)
 
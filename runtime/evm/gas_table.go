@@ -0,0 +1,60 @@
+package evm
+
+import (
+	"math/big"
+)
+
+// gasFunc returns the gas of an instruction that depends on its arguments.
+// The sizes of the memory areas are already checked when it is called.
+type gasFunc func(c *state) uint64
+
+// toWords returns the number of 32 bytes words to fit the size
+func toWords(size *big.Int) uint64 {
+	return (size.Uint64() + 31) / 32
+}
+
+func gasExp(c *state) uint64 {
+	return uint64((c.peekAt(2).BitLen()+7)/8) * 10
+}
+
+// gasExpEIP160 is the repriced exp byte cost (eip-160)
+func gasExpEIP160(c *state) uint64 {
+	return uint64((c.peekAt(2).BitLen()+7)/8) * 50
+}
+
+func gasSha3(c *state) uint64 {
+	return toWords(c.peekAt(2)) * sha3WordGas
+}
+
+// gasCopy is the gas of the copy instructions with the size as third argument
+func gasCopy(c *state) uint64 {
+	return toWords(c.peekAt(3)) * copyGas
+}
+
+func gasExtCodeCopy(c *state) uint64 {
+	return toWords(c.peekAt(4)) * copyGas
+}
+
+func gasLog(topics int) gasFunc {
+	return func(c *state) uint64 {
+		return uint64(topics)*375 + c.peekAt(2).Uint64()*8
+	}
+}
+
+// gasAccountAccessEIP2929 is the cost to access the address on top of the stack
+func gasAccountAccessEIP2929(c *state) uint64 {
+	return c.addressAccessCost(bigToAddress(c.top()))
+}
+
+func gasExtCodeCopyEIP2929(c *state) uint64 {
+	return gasExtCodeCopy(c) + c.addressAccessCost(bigToAddress(c.top()))
+}
+
+func gasSloadEIP2929(c *state) uint64 {
+	loc := bigToHash(c.top())
+	if _, slotOk := c.host.SlotInAccessList(c.msg.Address, loc); !slotOk {
+		c.host.AddSlotToAccessList(c.msg.Address, loc)
+		return coldSloadCost
+	}
+	return warmStorageReadCost
+}
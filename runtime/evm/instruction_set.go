@@ -0,0 +1,261 @@
+package evm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/0xPolygon/eth-state-transition/runtime"
+)
+
+type handler struct {
+	inst  instruction
+	stack int
+	gas   uint64
+
+	// dynamicGas is the gas that depends on the arguments of the instruction.
+	// It is charged after the memory expansion.
+	dynamicGas gasFunc
+
+	// memorySize is the memory area accessed by the instruction. The memory
+	// is expanded and charged before the instruction runs.
+	memorySize memorySizeFunc
+}
+
+// instructionSet is the immutable table of the instructions enabled
+// in a set of forks
+type instructionSet [256]handler
+
+func (s *instructionSet) register(op OpCode, h handler) {
+	if s[op].inst != nil {
+		panic(fmt.Errorf("instruction already exists"))
+	}
+	s[op] = h
+}
+
+func (s *instructionSet) registerRange(from, to OpCode, factory func(n int) instruction, stack func(n int) int, gas uint64) {
+	c := 1
+	for i := from; i <= to; i++ {
+		s.register(i, handler{inst: factory(c), stack: stack(c), gas: gas})
+		c++
+	}
+}
+
+// frontierInstructionSet is the set of instructions before any eip
+var frontierInstructionSet = newFrontierInstructionSet()
+
+func newFrontierInstructionSet() instructionSet {
+	var s instructionSet
+
+	// unsigned arithmetic operations
+	s.register(STOP, handler{inst: opStop, stack: 0, gas: 0})
+	s.register(ADD, handler{inst: opAdd, stack: 2, gas: 3})
+	s.register(SUB, handler{inst: opSub, stack: 2, gas: 3})
+	s.register(MUL, handler{inst: opMul, stack: 2, gas: 5})
+	s.register(DIV, handler{inst: opDiv, stack: 2, gas: 5})
+	s.register(SDIV, handler{inst: opSDiv, stack: 2, gas: 5})
+	s.register(MOD, handler{inst: opMod, stack: 2, gas: 5})
+	s.register(SMOD, handler{inst: opSMod, stack: 2, gas: 5})
+	s.register(EXP, handler{inst: opExp, stack: 2, gas: 10, dynamicGas: gasExp})
+
+	s.registerRange(PUSH1, PUSH32, opPush, func(n int) int { return 0 }, 3)
+	s.registerRange(DUP1, DUP16, opDup, func(n int) int { return n }, 3)
+	s.registerRange(SWAP1, SWAP16, opSwap, func(n int) int { return n + 1 }, 3)
+	s.registerRange(LOG0, LOG4, opLog, func(n int) int { return n + 1 }, 375)
+	for op := LOG0; op <= LOG4; op++ {
+		s[op].dynamicGas = gasLog(int(op - LOG0))
+		s[op].memorySize = memoryLog
+	}
+
+	s.register(ADDMOD, handler{inst: opAddMod, stack: 3, gas: 8})
+	s.register(MULMOD, handler{inst: opMulMod, stack: 3, gas: 8})
+
+	s.register(AND, handler{inst: opAnd, stack: 2, gas: 3})
+	s.register(OR, handler{inst: opOr, stack: 2, gas: 3})
+	s.register(XOR, handler{inst: opXor, stack: 2, gas: 3})
+	s.register(BYTE, handler{inst: opByte, stack: 2, gas: 3})
+
+	s.register(NOT, handler{inst: opNot, stack: 1, gas: 3})
+	s.register(ISZERO, handler{inst: opIsZero, stack: 1, gas: 3})
+
+	s.register(EQ, handler{inst: opEq, stack: 2, gas: 3})
+	s.register(LT, handler{inst: opLt, stack: 2, gas: 3})
+	s.register(GT, handler{inst: opGt, stack: 2, gas: 3})
+	s.register(SLT, handler{inst: opSlt, stack: 2, gas: 3})
+	s.register(SGT, handler{inst: opSgt, stack: 2, gas: 3})
+
+	s.register(SIGNEXTEND, handler{inst: opSignExtension, stack: 1, gas: 5})
+
+	s.register(CREATE, handler{inst: opCreate(CREATE), stack: 3, gas: 32000, memorySize: memoryCreate})
+
+	s.register(CALL, handler{inst: opCall(CALL), stack: 7, gas: 0, memorySize: memoryCall})
+	s.register(CALLCODE, handler{inst: opCall(CALLCODE), stack: 7, gas: 0, memorySize: memoryCall})
+
+	s.register(RETURN, handler{inst: opHalt(RETURN), stack: 2, gas: 0, memorySize: memoryReturn})
+
+	// memory
+	s.register(MLOAD, handler{inst: opMload, stack: 1, gas: 3, memorySize: memoryMLoad})
+	s.register(MSTORE, handler{inst: opMStore, stack: 2, gas: 3, memorySize: memoryMStore})
+	s.register(MSTORE8, handler{inst: opMStore8, stack: 2, gas: 3, memorySize: memoryMStore8})
+
+	// store
+	s.register(SLOAD, handler{inst: opSload, stack: 1, gas: 50})
+	s.register(SSTORE, handler{inst: opSStore, stack: 2, gas: 0})
+
+	s.register(SHA3, handler{inst: opSha3, stack: 2, gas: 30, dynamicGas: gasSha3, memorySize: memorySha3})
+
+	s.register(POP, handler{inst: opPop, stack: 1, gas: 2})
+
+	// context operations
+	s.register(ADDRESS, handler{inst: opAddress, stack: 0, gas: 2})
+	s.register(BALANCE, handler{inst: opBalance, stack: 1, gas: 20})
+	s.register(ORIGIN, handler{inst: opOrigin, stack: 0, gas: 2})
+	s.register(CALLER, handler{inst: opCaller, stack: 0, gas: 2})
+	s.register(CALLVALUE, handler{inst: opCallValue, stack: 0, gas: 2})
+	s.register(CALLDATALOAD, handler{inst: opCallDataLoad, stack: 1, gas: 3})
+	s.register(CALLDATASIZE, handler{inst: opCallDataSize, stack: 0, gas: 2})
+	s.register(CODESIZE, handler{inst: opCodeSize, stack: 0, gas: 2})
+	s.register(EXTCODESIZE, handler{inst: opExtCodeSize, stack: 1, gas: 20})
+	s.register(GASPRICE, handler{inst: opGasPrice, stack: 0, gas: 2})
+	s.register(PC, handler{inst: opPC, stack: 0, gas: 2})
+	s.register(MSIZE, handler{inst: opMSize, stack: 0, gas: 2})
+	s.register(GAS, handler{inst: opGas, stack: 0, gas: 2})
+
+	s.register(EXTCODECOPY, handler{inst: opExtCodeCopy, stack: 4, gas: 20, dynamicGas: gasExtCodeCopy, memorySize: memoryExtCodeCopy})
+
+	s.register(CALLDATACOPY, handler{inst: opCallDataCopy, stack: 3, gas: 3, dynamicGas: gasCopy, memorySize: memoryCopy})
+	s.register(CODECOPY, handler{inst: opCodeCopy, stack: 3, gas: 3, dynamicGas: gasCopy, memorySize: memoryCopy})
+
+	// block information
+	s.register(BLOCKHASH, handler{inst: opBlockHash, stack: 1, gas: 20})
+	s.register(COINBASE, handler{inst: opCoinbase, stack: 0, gas: 2})
+	s.register(TIMESTAMP, handler{inst: opTimestamp, stack: 0, gas: 2})
+	s.register(NUMBER, handler{inst: opNumber, stack: 0, gas: 2})
+	s.register(DIFFICULTY, handler{inst: opDifficulty, stack: 0, gas: 2})
+	s.register(GASLIMIT, handler{inst: opGasLimit, stack: 0, gas: 2})
+
+	s.register(SELFDESTRUCT, handler{inst: opSelfDestruct, stack: 1, gas: 0})
+
+	// jumps
+	s.register(JUMP, handler{inst: opJump, stack: 1, gas: 8})
+	s.register(JUMPI, handler{inst: opJumpi, stack: 2, gas: 10})
+	s.register(JUMPDEST, handler{inst: opJumpDest, stack: 0, gas: 1})
+
+	return s
+}
+
+// eipInstructions are the changes of each eip to the instruction set.
+// They are applied in order on top of the frontier set.
+var eipInstructions = []struct {
+	eip    runtime.EIP
+	enable func(s *instructionSet)
+}{
+	{runtime.EIP7, func(s *instructionSet) {
+		s.register(DELEGATECALL, handler{inst: opCall(DELEGATECALL), stack: 6, gas: 0, memorySize: memoryDelegateCall})
+	}},
+	{runtime.EIP150, func(s *instructionSet) {
+		s[BALANCE].gas = 400
+		s[EXTCODESIZE].gas = 700
+		s[EXTCODECOPY].gas = 700
+		s[SLOAD].gas = 200
+	}},
+	{runtime.EIP160, func(s *instructionSet) {
+		s[EXP].dynamicGas = gasExpEIP160
+	}},
+	{runtime.EIP140, func(s *instructionSet) {
+		s.register(REVERT, handler{inst: opHalt(REVERT), stack: 2, gas: 0, memorySize: memoryReturn})
+	}},
+	{runtime.EIP211, func(s *instructionSet) {
+		s.register(RETURNDATASIZE, handler{inst: opReturnDataSize, stack: 0, gas: 2})
+		s.register(RETURNDATACOPY, handler{inst: opReturnDataCopy, stack: 3, gas: 3, dynamicGas: gasCopy, memorySize: memoryCopy})
+	}},
+	{runtime.EIP214, func(s *instructionSet) {
+		s.register(STATICCALL, handler{inst: opCall(STATICCALL), stack: 6, gas: 0, memorySize: memoryDelegateCall})
+	}},
+	{runtime.EIP145, func(s *instructionSet) {
+		s.register(SHL, handler{inst: opShl, stack: 2, gas: 3})
+		s.register(SHR, handler{inst: opShr, stack: 2, gas: 3})
+		s.register(SAR, handler{inst: opSar, stack: 2, gas: 3})
+	}},
+	{runtime.EIP1014, func(s *instructionSet) {
+		s.register(CREATE2, handler{inst: opCreate(CREATE2), stack: 4, gas: 32000, memorySize: memoryCreate})
+	}},
+	{runtime.EIP1052, func(s *instructionSet) {
+		s.register(EXTCODEHASH, handler{inst: opExtCodeHash, stack: 1, gas: 400})
+	}},
+	{runtime.EIP1344, func(s *instructionSet) {
+		s.register(CHAINID, handler{inst: opChainID, stack: 0, gas: 2})
+	}},
+	{runtime.EIP1884, func(s *instructionSet) {
+		s.register(SELFBALANCE, handler{inst: opSelfBalance, stack: 0, gas: 5})
+		s[BALANCE].gas = 700
+		s[EXTCODEHASH].gas = 700
+		s[SLOAD].gas = 800
+	}},
+	{runtime.EIP2929, func(s *instructionSet) {
+		// the access cost is charged as dynamic gas
+		for _, op := range []OpCode{BALANCE, EXTCODESIZE, EXTCODEHASH} {
+			s[op].gas = 0
+			s[op].dynamicGas = gasAccountAccessEIP2929
+		}
+		s[EXTCODECOPY].gas = 0
+		s[EXTCODECOPY].dynamicGas = gasExtCodeCopyEIP2929
+		s[SLOAD].gas = 0
+		s[SLOAD].dynamicGas = gasSloadEIP2929
+	}},
+	{runtime.EIP3198, func(s *instructionSet) {
+		s.register(BASEFEE, handler{inst: opBaseFee, stack: 0, gas: 2})
+	}},
+	{runtime.EIP4399, func(s *instructionSet) {
+		s[DIFFICULTY].inst = opRandom
+	}},
+	{runtime.EIP3855, func(s *instructionSet) {
+		s.register(PUSH0, handler{inst: opPush0, stack: 0, gas: 2})
+	}},
+	{runtime.EIP1153, func(s *instructionSet) {
+		s.register(TLOAD, handler{inst: opTload, stack: 1, gas: 100})
+		s.register(TSTORE, handler{inst: opTstore, stack: 2, gas: 100})
+	}},
+	{runtime.EIP4844, func(s *instructionSet) {
+		s.register(BLOBHASH, handler{inst: opBlobHash, stack: 1, gas: 3})
+	}},
+	{runtime.EIP5656, func(s *instructionSet) {
+		s.register(MCOPY, handler{inst: opMCopy, stack: 3, gas: 3, dynamicGas: gasCopy, memorySize: memoryMCopy})
+	}},
+	{runtime.EIP7516, func(s *instructionSet) {
+		s.register(BLOBBASEFEE, handler{inst: opBlobBaseFee, stack: 0, gas: 2})
+	}},
+	{runtime.EIP7939, func(s *instructionSet) {
+		s.register(CLZ, handler{inst: opClz, stack: 1, gas: 5})
+	}},
+}
+
+func init() {
+	if len(eipInstructions) > 64 {
+		panic(fmt.Errorf("too many eips for the instruction set key"))
+	}
+}
+
+// instructionSets caches the instruction sets by the eips enabled
+var instructionSets sync.Map
+
+// instructionSetFor returns the instruction set of the enabled eips
+func instructionSetFor(config *runtime.ForksInTime) *instructionSet {
+	var key uint64
+	for i, change := range eipInstructions {
+		if config.Enabled(change.eip) {
+			key |= 1 << uint(i)
+		}
+	}
+	if s, ok := instructionSets.Load(key); ok {
+		return s.(*instructionSet)
+	}
+
+	s := frontierInstructionSet
+	for i, change := range eipInstructions {
+		if key&(1<<uint(i)) != 0 {
+			change.enable(&s)
+		}
+	}
+	actual, _ := instructionSets.LoadOrStore(key, &s)
+	return actual.(*instructionSet)
+}
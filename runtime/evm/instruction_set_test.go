@@ -0,0 +1,108 @@
+package evm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushOpcodes(t *testing.T) {
+	code := make([]byte, 33)
+	for i := 0; i < 33; i++ {
+		code[i] = byte(i + 1)
+	}
+
+	c := 1
+	for i := PUSH1; i <= PUSH32; i++ {
+		s := &state{
+			code: code,
+		}
+
+		inst := frontierInstructionSet[i]
+		inst.inst(s)
+
+		assert.False(t, s.stop)
+
+		res := s.pop().Bytes()
+		assert.Len(t, res, c)
+
+		assert.True(t, bytes.HasPrefix(code[1:], res))
+		c++
+	}
+}
+
+// runOp runs a single instruction through the interpreter loop
+func runOp(s *state, config *runtime.ForksInTime, op OpCode) error {
+	s.config = config
+	s.instructions = instructionSetFor(config)
+	s.limits = config.GetLimits()
+	s.code = []byte{byte(op)}
+	s.ip = 0
+	s.stop = false
+	s.err = nil
+
+	_, err := s.Run()
+	return err
+}
+
+func TestPush0Opcode(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	// push0 is not enabled before shanghai
+	assert.Equal(t, errOpCodeNotFound, runOp(s, &runtime.ForksInTime{}, PUSH0))
+
+	s.gas = 2
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{Shanghai: true}, PUSH0))
+	assert.Equal(t, 0, s.pop().Sign())
+
+	// push0 can be enabled without the rest of shanghai
+	s.gas = 2
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{EIPs: map[runtime.EIP]bool{runtime.EIP3855: true}}, PUSH0))
+	assert.Equal(t, 0, s.pop().Sign())
+}
+
+func TestInstructionSetForks(t *testing.T) {
+	frontier := instructionSetFor(&runtime.ForksInTime{})
+	for _, op := range []OpCode{DELEGATECALL, REVERT, STATICCALL, SHL, CREATE2, EXTCODEHASH, CHAINID, SELFBALANCE, BASEFEE, PUSH0, TLOAD, MCOPY, BLOBHASH, BLOBBASEFEE, CLZ} {
+		assert.Nil(t, frontier[op].inst, op.String())
+	}
+	assert.Equal(t, uint64(50), frontier[SLOAD].gas)
+	assert.Equal(t, uint64(20), frontier[BALANCE].gas)
+
+	istanbul := instructionSetFor(&runtime.ForksInTime{Homestead: true, EIP150: true, EIP158: true, Byzantium: true, Constantinople: true, Petersburg: true, Istanbul: true})
+	for _, op := range []OpCode{DELEGATECALL, REVERT, STATICCALL, SHL, CREATE2, EXTCODEHASH, CHAINID, SELFBALANCE} {
+		assert.NotNil(t, istanbul[op].inst, op.String())
+	}
+	assert.Nil(t, istanbul[BASEFEE].inst)
+	assert.Equal(t, uint64(800), istanbul[SLOAD].gas)
+	assert.Equal(t, uint64(700), istanbul[BALANCE].gas)
+	assert.Equal(t, uint64(700), istanbul[EXTCODEHASH].gas)
+
+	// the access costs are dynamic after berlin
+	berlin := instructionSetFor(&runtime.ForksInTime{Homestead: true, EIP150: true, Istanbul: true, Berlin: true})
+	assert.Equal(t, uint64(0), berlin[SLOAD].gas)
+	assert.NotNil(t, berlin[SLOAD].dynamicGas)
+
+	// the sets are cached by the enabled eips
+	assert.Same(t, istanbul, instructionSetFor(&runtime.ForksInTime{Homestead: true, EIP150: true, EIP158: true, Byzantium: true, Constantinople: true, Petersburg: true, Istanbul: true}))
+}
+
+func TestInstructionSetMemory(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	// the memory is expanded and charged before the instruction
+	s.gas = 1000
+	s.push(big.NewInt(64)) // length
+	s.push(big.NewInt(0))  // data offset
+	s.push(big.NewInt(32)) // memory offset
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{}, CALLDATACOPY))
+
+	assert.Len(t, s.memory, 96)
+	// 3 static + 3 words of memory + 2 words of copy
+	assert.Equal(t, uint64(1000-3-9-2*copyGas), s.gas)
+}
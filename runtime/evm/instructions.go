@@ -121,17 +121,6 @@ func opExp(c *state) {
 	x := c.pop()
 	y := c.top()
 
-	var gas uint64
-	if c.config.EIP158 {
-		gas = 50
-	} else {
-		gas = 10
-	}
-	gasCost := uint64((y.BitLen()+7)/8) * gas
-	if !c.consumeGas(gasCost) {
-		return
-	}
-
 	z := acquireBig().Set(one)
 
 	// https://www.programminglogic.com/fast-exponentiation-algorithms/
@@ -320,11 +309,6 @@ func equalOrOverflowsUint256(b *big.Int) bool {
 }
 
 func opShl(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	shift := c.pop()
 	value := c.top()
 
@@ -337,11 +321,6 @@ func opShl(c *state) {
 }
 
 func opShr(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	shift := c.pop()
 	value := c.top()
 
@@ -354,11 +333,6 @@ func opShr(c *state) {
 }
 
 func opSar(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	shift := c.pop()
 	value := to256(c.top())
 
@@ -374,6 +348,11 @@ func opSar(c *state) {
 	}
 }
 
+func opClz(c *state) {
+	v := c.top()
+	v.SetUint64(256 - uint64(v.BitLen()))
+}
+
 // memory operations
 
 var bufPool = sync.Pool{
@@ -443,24 +422,47 @@ func opMStore8(c *state) {
 
 // --- storage ---
 
+const (
+	// eip-2929 access costs
+	coldAccountAccessCost uint64 = 2600
+	coldSloadCost         uint64 = 2100
+	warmStorageReadCost   uint64 = 100
+)
+
+// addressAccessCost returns the eip-2929 cost to access the address
+// and adds it to the access list if it was not there yet
+func (c *state) addressAccessCost(addr types.Address) uint64 {
+	if c.host.AddressInAccessList(addr) {
+		return warmStorageReadCost
+	}
+	c.host.AddAddressToAccessList(addr)
+	return coldAccountAccessCost
+}
+
 func opSload(c *state) {
 	loc := c.top()
 
-	var gas uint64
-	if c.config.Istanbul {
-		// eip-1884
-		gas = 800
-	} else if c.config.EIP150 {
-		gas = 200
-	} else {
-		gas = 50
-	}
-	if !c.consumeGas(gas) {
+	val := c.host.GetStorage(c.msg.Address, bigToHash(loc))
+	loc.SetBytes(val.Bytes())
+}
+
+func opTload(c *state) {
+	loc := c.top()
+
+	val := c.host.GetTransientStorage(c.msg.Address, bigToHash(loc))
+	loc.SetBytes(val.Bytes())
+}
+
+func opTstore(c *state) {
+	if c.inStaticCall() {
+		c.exit(errWriteProtection)
 		return
 	}
 
-	val := c.host.GetStorage(c.msg.Address, bigToHash(loc))
-	loc.SetBytes(val.Bytes())
+	key := c.popHash()
+	val := c.popHash()
+
+	c.host.SetTransientStorage(c.msg.Address, key, val)
 }
 
 func opSStore(c *state) {
@@ -469,7 +471,7 @@ func opSStore(c *state) {
 		return
 	}
 
-	if c.config.Istanbul && c.gas <= 2300 {
+	if c.config.Enabled(runtime.EIP2200) && c.gas <= 2300 {
 		c.exit(errOutOfGas)
 		return
 	}
@@ -477,14 +479,24 @@ func opSStore(c *state) {
 	key := c.popHash()
 	val := c.popHash()
 
-	legacyGasMetering := !c.config.Istanbul && (c.config.Petersburg || !c.config.Constantinople)
+	legacyGasMetering := !c.config.Enabled(runtime.EIP2200) && !c.config.Enabled(runtime.EIP1283)
 
-	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
 	cost := uint64(0)
+	if c.config.Enabled(runtime.EIP2929) {
+		// eip-2929
+		if _, slotOk := c.host.SlotInAccessList(c.msg.Address, key); !slotOk {
+			c.host.AddSlotToAccessList(c.msg.Address, key)
+			cost = coldSloadCost
+		}
+	}
+
+	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
 
 	switch status {
 	case runtime.StorageUnchanged:
-		if c.config.Istanbul {
+		if c.config.Enabled(runtime.EIP2929) {
+			cost += warmStorageReadCost
+		} else if c.config.Enabled(runtime.EIP2200) {
 			// eip-2200
 			cost = 800
 		} else if legacyGasMetering {
@@ -494,10 +506,16 @@ func opSStore(c *state) {
 		}
 
 	case runtime.StorageModified:
-		cost = 5000
+		if c.config.Enabled(runtime.EIP2929) {
+			cost += 5000 - coldSloadCost
+		} else {
+			cost = 5000
+		}
 
 	case runtime.StorageModifiedAgain:
-		if c.config.Istanbul {
+		if c.config.Enabled(runtime.EIP2929) {
+			cost += warmStorageReadCost
+		} else if c.config.Enabled(runtime.EIP2200) {
 			// eip-2200
 			cost = 800
 		} else if legacyGasMetering {
@@ -507,10 +525,14 @@ func opSStore(c *state) {
 		}
 
 	case runtime.StorageAdded:
-		cost = 20000
+		cost += 20000
 
 	case runtime.StorageDeleted:
-		cost = 5000
+		if c.config.Enabled(runtime.EIP2929) {
+			cost += 5000 - coldSloadCost
+		} else {
+			cost = 5000
+		}
 	}
 	if !c.consumeGas(cost) {
 		return
@@ -528,11 +550,6 @@ func opSha3(c *state) {
 		return
 	}
 
-	size := length.Uint64()
-	if !c.consumeGas(((size + 31) / 32) * sha3WordGas) {
-		return
-	}
-
 	c.tmp = helper.Keccak256To(c.tmp[:0], c.tmp)
 
 	v := c.push1()
@@ -552,38 +569,14 @@ func opAddress(c *state) {
 func opBalance(c *state) {
 	addr, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.Istanbul {
-		// eip-1884
-		gas = 700
-	} else if c.config.EIP150 {
-		gas = 400
-	} else {
-		gas = 20
-	}
-
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	c.push1().Set(c.host.GetBalance(addr))
 }
 
 func opSelfBalance(c *state) {
-	if !c.config.Istanbul {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	c.push1().Set(c.host.GetBalance(c.msg.Address))
 }
 
 func opChainID(c *state) {
-	if !c.config.Istanbul {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	c.push1().SetUint64(uint64(c.host.GetTxContext().ChainID))
 }
 
@@ -632,16 +625,6 @@ func opCodeSize(c *state) {
 func opExtCodeSize(c *state) {
 	addr, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.EIP150 {
-		gas = 700
-	} else {
-		gas = 20
-	}
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	c.push1().SetUint64(uint64(c.host.GetCodeSize(addr)))
 }
 
@@ -650,31 +633,12 @@ func opGasPrice(c *state) {
 }
 
 func opReturnDataSize(c *state) {
-	if !c.config.Byzantium {
-		c.exit(errOpCodeNotFound)
-	} else {
-		c.push1().SetUint64(uint64(len(c.returnData)))
-	}
+	c.push1().SetUint64(uint64(len(c.returnData)))
 }
 
 func opExtCodeHash(c *state) {
-	if !c.config.Constantinople {
-		c.exit(errOpCodeNotFound)
-		return
-	}
-
 	address, _ := c.popAddr()
 
-	var gas uint64
-	if c.config.Istanbul {
-		gas = 700
-	} else {
-		gas = 400
-	}
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	v := c.push1()
 	if c.host.Empty(address) {
 		v.Set(zero)
@@ -732,20 +696,6 @@ func opExtCodeCopy(c *state) {
 	}
 
 	size := length.Uint64()
-	if !c.consumeGas(((size + 31) / 32) * copyGas) {
-		return
-	}
-
-	var gas uint64
-	if c.config.EIP150 {
-		gas = 700
-	} else {
-		gas = 20
-	}
-	if !c.consumeGas(gas) {
-		return
-	}
-
 	code := c.host.GetCode(address)
 	if size != 0 {
 		c.setBytes(c.memory[memOffset.Uint64():], code, size, codeOffset)
@@ -762,21 +712,28 @@ func opCallDataCopy(c *state) {
 	}
 
 	size := length.Uint64()
-	if !c.consumeGas(((size + 31) / 32) * copyGas) {
-		return
-	}
 
 	if size != 0 {
 		c.setBytes(c.memory[memOffset.Uint64():], c.msg.Input, size, dataOffset)
 	}
 }
 
-func opReturnDataCopy(c *state) {
-	if !c.config.Byzantium {
-		c.exit(errOpCodeNotFound)
-		return
+func opMCopy(c *state) {
+	dstOffset := c.pop()
+	srcOffset := c.pop()
+	length := c.pop()
+
+	// the memory is already expanded to cover both areas (eip-5656)
+	size := length.Uint64()
+
+	if size != 0 {
+		src := srcOffset.Uint64()
+		dst := dstOffset.Uint64()
+		copy(c.memory[dst:dst+size], c.memory[src:src+size])
 	}
+}
 
+func opReturnDataCopy(c *state) {
 	memOffset := c.pop()
 	dataOffset := c.pop()
 	length := c.pop()
@@ -786,9 +743,6 @@ func opReturnDataCopy(c *state) {
 	}
 
 	size := length.Uint64()
-	if !c.consumeGas(((size + 31) / 32) * copyGas) {
-		return
-	}
 
 	end := length.Add(dataOffset, length)
 	if !end.IsUint64() {
@@ -815,9 +769,6 @@ func opCodeCopy(c *state) {
 	}
 
 	size := length.Uint64()
-	if !c.consumeGas(((size + 31) / 32) * copyGas) {
-		return
-	}
 	if size != 0 {
 		c.setBytes(c.memory[memOffset.Uint64():], c.code, size, dataOffset)
 	}
@@ -859,10 +810,34 @@ func opDifficulty(c *state) {
 	c.push1().SetBytes(c.host.GetTxContext().Difficulty.Bytes())
 }
 
+// opRandom replaces DIFFICULTY after the merge (eip-4399)
+func opRandom(c *state) {
+	c.push1().SetBytes(c.host.GetTxContext().Random.Bytes())
+}
+
 func opGasLimit(c *state) {
 	c.push1().SetInt64(c.host.GetTxContext().GasLimit)
 }
 
+func opBaseFee(c *state) {
+	c.push1().SetBytes(c.host.GetTxContext().BaseFee.Bytes())
+}
+
+func opBlobHash(c *state) {
+	index := c.top()
+
+	hashes := c.host.GetTxContext().BlobHashes
+	if index.IsUint64() && index.Uint64() < uint64(len(hashes)) {
+		index.SetBytes(hashes[index.Uint64()].Bytes())
+	} else {
+		index.Set(zero)
+	}
+}
+
+func opBlobBaseFee(c *state) {
+	c.push1().SetBytes(c.host.GetTxContext().BlobBaseFee.Bytes())
+}
+
 func opSelfDestruct(c *state) {
 	if c.inStaticCall() {
 		c.exit(errWriteProtection)
@@ -875,9 +850,14 @@ func opSelfDestruct(c *state) {
 	var gas uint64
 
 	// EIP150 reprice fork
-	if c.config.EIP150 {
+	if c.config.Enabled(runtime.EIP150) {
 		gas = 5000
-		if c.config.EIP158 {
+		if c.config.Enabled(runtime.EIP2929) && !c.host.AddressInAccessList(address) {
+			// eip-2929
+			c.host.AddAddressToAccessList(address)
+			gas += coldAccountAccessCost
+		}
+		if c.config.Enabled(runtime.EIP161) {
 			// if empty and transfers value
 			if c.host.Empty(address) && c.host.GetBalance(c.msg.Address).Sign() != 0 {
 				gas += 25000
@@ -921,6 +901,10 @@ func opJumpi(c *state) {
 func opJumpDest(c *state) {
 }
 
+func opPush0(c *state) {
+	c.push1().Set(zero)
+}
+
 func opPush(n int) instruction {
 	return func(c *state) {
 		ins := c.code
@@ -939,22 +923,14 @@ func opPush(n int) instruction {
 
 func opDup(n int) instruction {
 	return func(c *state) {
-		if !c.stackAtLeast(n) {
-			c.exit(errStackUnderflow)
-		} else {
-			val := c.peekAt(n)
-			c.push1().Set(val)
-		}
+		val := c.peekAt(n)
+		c.push1().Set(val)
 	}
 }
 
 func opSwap(n int) instruction {
 	return func(c *state) {
-		if !c.stackAtLeast(n + 1) {
-			c.exit(errStackUnderflow)
-		} else {
-			c.swap(n)
-		}
+		c.swap(n)
 	}
 }
 
@@ -966,11 +942,6 @@ func opLog(size int) instruction {
 			return
 		}
 
-		if !c.stackAtLeast(2 + size) {
-			c.exit(errStackUnderflow)
-			return
-		}
-
 		mStart := c.pop()
 		mSize := c.pop()
 
@@ -986,13 +957,6 @@ func opLog(size int) instruction {
 		}
 
 		c.host.EmitLog(c.msg.Address, topics, c.tmp)
-
-		if !c.consumeGas(uint64(size) * 375) {
-			return
-		}
-		if !c.consumeGas(mSize.Uint64() * 8) {
-			return
-		}
 	}
 }
 
@@ -1007,13 +971,6 @@ func opCreate(op OpCode) instruction {
 			return
 		}
 
-		if op == CREATE2 {
-			if !c.config.Constantinople {
-				c.exit(errOpCodeNotFound)
-				return
-			}
-		}
-
 		// reset the return data
 		c.resetReturnData()
 
@@ -1035,7 +992,7 @@ func opCreate(op OpCode) instruction {
 		result := c.host.Callx(contract, c.host)
 
 		v := c.push1()
-		if op == CREATE && c.config.Homestead && result.Err == runtime.ErrCodeStoreOutOfGas {
+		if op == CREATE && c.config.Enabled(runtime.EIP2) && result.Err == runtime.ErrCodeStoreOutOfGas {
 			v.Set(zero)
 		} else if result.Failed() && result.Err != runtime.ErrCodeStoreOutOfGas {
 			v.Set(zero)
@@ -1062,15 +1019,6 @@ func opCall(op OpCode) instruction {
 			}
 		}
 
-		if op == DELEGATECALL && !c.config.Homestead {
-			c.exit(errOpCodeNotFound)
-			return
-		}
-		if op == STATICCALL && !c.config.Byzantium {
-			c.exit(errOpCodeNotFound)
-			return
-		}
-
 		var callType runtime.CallType
 		switch op {
 		case CALL:
@@ -1152,17 +1100,29 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 	}
 
 	var gasCost uint64
-	if c.config.EIP150 {
+	if c.config.Enabled(runtime.EIP2929) {
+		// eip-2929
+		gasCost = c.addressAccessCost(addr)
+	} else if c.config.Enabled(runtime.EIP150) {
 		gasCost = 700
 	} else {
 		gasCost = 40
 	}
 
-	eip158 := c.config.EIP158
+	code := c.host.GetCode(addr)
+	if c.config.Enabled(runtime.EIP7702) {
+		// eip-7702: execute the code of the delegation target
+		if target, ok := runtime.ParseDelegation(code); ok {
+			gasCost += c.addressAccessCost(target)
+			code = c.host.GetCode(target)
+		}
+	}
+
+	eip161 := c.config.Enabled(runtime.EIP161)
 	transfersValue := (op == CALL || op == CALLCODE) && value != nil && value.Sign() != 0
 
 	if op == CALL {
-		if eip158 {
+		if eip161 {
 			if transfersValue && c.host.Empty(addr) {
 				gasCost += 25000
 			}
@@ -1177,7 +1137,7 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 	var gas uint64
 
 	ok = initialGas.IsUint64()
-	if c.config.EIP150 {
+	if c.config.Enabled(runtime.EIP150) {
 		availableGas := c.gas - gasCost
 		availableGas = availableGas - availableGas/64
 
@@ -1206,7 +1166,7 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 
 	parent := c
 
-	contract := runtime.NewContractCall(c.msg.Depth+1, parent.msg.Origin, parent.msg.Address, addr, value, gas, c.host.GetCode(addr), args)
+	contract := runtime.NewContractCall(c.msg.Depth+1, parent.msg.Origin, parent.msg.Address, addr, value, gas, code, args)
 
 	if op == STATICCALL || parent.msg.Static {
 		contract.Static = true
@@ -1266,6 +1226,17 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 		}
 	}
 
+	if c.config.Enabled(runtime.EIP3860) {
+		// eip-3860: limit and meter initcode
+		if len(input) > c.limits.MaxInitCodeSize {
+			c.exit(runtime.ErrMaxInitCodeSizeExceeded)
+			return nil, nil
+		}
+		if !c.consumeGas(((uint64(len(input)) + 31) / 32) * runtime.InitCodeWordGas) {
+			return nil, nil
+		}
+	}
+
 	if op == CREATE2 {
 		// Consume sha3 gas cost
 		size := length.Uint64()
@@ -1278,7 +1249,7 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 	gas := c.gas
 
 	// CREATE2 uses by default EIP150
-	if c.config.EIP150 || op == CREATE2 {
+	if c.config.Enabled(runtime.EIP150) || op == CREATE2 {
 		gas -= gas / 64
 	}
 
@@ -1299,11 +1270,6 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 
 func opHalt(op OpCode) instruction {
 	return func(c *state) {
-		if op == REVERT && !c.config.Byzantium {
-			c.exit(errOpCodeNotFound)
-			return
-		}
-
 		offset := c.pop()
 		size := c.pop()
 
@@ -213,44 +213,6 @@ func TestCreate(t *testing.T) {
 			},
 			mockHost: &mockHostForCreate{},
 		},
-		{
-			name:     "should throw errOpCodeNotFound when op is CREATE2 and config.Constantinople is disabled",
-			op:       CREATE2,
-			contract: &runtime.Contract{},
-			config: &runtime.ForksInTime{
-				Constantinople: false,
-			},
-			initState: &state{
-				gas: 1000,
-				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
-				},
-				memory: []byte{
-					byte(REVERT),
-				},
-				stop: false,
-				err:  nil,
-			},
-			// shouldn't change any states except for stop and err
-			resultState: &state{
-				gas: 1000,
-				sp:  3,
-				stack: []*big.Int{
-					big.NewInt(0x01), // length
-					big.NewInt(0x00), // offset
-					big.NewInt(0x00), // value
-				},
-				memory: []byte{
-					byte(REVERT),
-				},
-				stop: true,
-				err:  errOpCodeNotFound,
-			},
-			mockHost: &mockHostForCreate{},
-		},
 		{
 			name: "should set zero address if op is CREATE and contract call throws ErrCodeStoreOutOfGas",
 			op:   CREATE,
@@ -373,3 +335,298 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+type mockHostForTransient struct {
+	mockHost
+	storage map[types.Hash]types.Hash
+}
+
+func (m *mockHostForTransient) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	return m.storage[key]
+}
+
+func (m *mockHostForTransient) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	m.storage[key] = value
+}
+
+func TestTransientStorage(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	host := &mockHostForTransient{storage: map[types.Hash]types.Hash{}}
+
+	s.host = host
+	s.msg = &runtime.Contract{Address: addr1}
+	s.config = &runtime.ForksInTime{Cancun: true}
+
+	s.push(big.NewInt(2)) // value
+	s.push(big.NewInt(1)) // key
+	opTstore(s)
+	assert.False(t, s.stop)
+	assert.Equal(t, types.BytesToHash([]byte{2}), host.storage[types.BytesToHash([]byte{1})])
+
+	s.push(big.NewInt(1)) // key
+	opTload(s)
+	assert.False(t, s.stop)
+	assert.Equal(t, big.NewInt(2), s.pop())
+
+	// tstore is not allowed in a static call
+	s.msg.Static = true
+	s.push(big.NewInt(3)) // value
+	s.push(big.NewInt(1)) // key
+	opTstore(s)
+	assert.True(t, s.stop)
+	assert.Equal(t, errWriteProtection, s.err)
+}
+
+func TestMCopy(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	s.gas = 1000
+	s.memory = append(s.memory[:0], make([]byte, 64)...)
+	copy(s.memory, []byte{1, 2, 3, 4})
+
+	// overlapping copy of 4 bytes from offset 0 to offset 2
+	s.push(big.NewInt(4)) // length
+	s.push(big.NewInt(0)) // src offset
+	s.push(big.NewInt(2)) // dst offset
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{Cancun: true}, MCOPY))
+
+	assert.Equal(t, []byte{1, 2, 1, 2, 3, 4}, s.memory[:6])
+	assert.Equal(t, uint64(1000-3-copyGas), s.gas)
+
+	// the memory is expanded to the end of the source area
+	s.push(big.NewInt(32)) // length
+	s.push(big.NewInt(64)) // src offset
+	s.push(big.NewInt(0))  // dst offset
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{Cancun: true}, MCOPY))
+
+	assert.Len(t, s.memory, 96)
+	assert.Equal(t, make([]byte, 32), s.memory[:32])
+}
+
+type mockHostForTxContext struct {
+	mockHost
+	ctx runtime.TxContext
+}
+
+func (m *mockHostForTxContext) GetTxContext() runtime.TxContext {
+	return m.ctx
+}
+
+func TestBlobHash(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	hash := types.Hash{0x1, 0x2}
+
+	s.host = &mockHostForTxContext{ctx: runtime.TxContext{BlobHashes: []types.Hash{hash}}}
+	s.config = &runtime.ForksInTime{Cancun: true}
+
+	s.push(big.NewInt(0))
+	opBlobHash(s)
+	assert.Equal(t, new(big.Int).SetBytes(hash.Bytes()), s.pop())
+
+	// out of bounds indexes return zero
+	s.push(big.NewInt(1))
+	opBlobHash(s)
+	assert.Equal(t, 0, s.pop().Sign())
+}
+
+func TestPrevRandao(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	ctx := runtime.TxContext{
+		Difficulty: types.BytesToHash([]byte{0x1}),
+		Random:     types.BytesToHash([]byte{0x2}),
+	}
+	s.host = &mockHostForTxContext{ctx: ctx}
+
+	s.gas = 10
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{London: true}, DIFFICULTY))
+	assert.Equal(t, big.NewInt(1), s.pop())
+
+	s.gas = 10
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{London: true, Paris: true}, DIFFICULTY))
+	assert.Equal(t, big.NewInt(2), s.pop())
+}
+
+func TestClz(t *testing.T) {
+	s, close := getState()
+	defer close()
+
+	// clz is not enabled before osaka
+	s.gas = 10
+	s.push(big.NewInt(1))
+	assert.Equal(t, errOpCodeNotFound, runOp(s, &runtime.ForksInTime{Prague: true}, CLZ))
+
+	cases := []struct {
+		value    *big.Int
+		expected uint64
+	}{
+		{big.NewInt(0), 256},
+		{big.NewInt(1), 255},
+		{big.NewInt(0xff), 248},
+		{new(big.Int).Lsh(big.NewInt(1), 255), 0},
+		{new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)), 0},
+	}
+
+	for _, c := range cases {
+		s, close := getState()
+
+		s.config = &runtime.ForksInTime{Osaka: true}
+		s.push(c.value)
+		opClz(s)
+
+		assert.False(t, s.stop)
+		assert.Equal(t, c.expected, s.pop().Uint64())
+		close()
+	}
+}
+
+type mockHostForAccessList struct {
+	mockHost
+	addrs  map[types.Address]struct{}
+	slots  map[types.Hash]struct{}
+	status runtime.StorageStatus
+}
+
+func newMockHostForAccessList() *mockHostForAccessList {
+	return &mockHostForAccessList{
+		addrs: map[types.Address]struct{}{},
+		slots: map[types.Hash]struct{}{},
+	}
+}
+
+func (m *mockHostForAccessList) AddressInAccessList(addr types.Address) bool {
+	_, ok := m.addrs[addr]
+	return ok
+}
+
+func (m *mockHostForAccessList) SlotInAccessList(addr types.Address, slot types.Hash) (bool, bool) {
+	_, ok := m.slots[slot]
+	return m.AddressInAccessList(addr), ok
+}
+
+func (m *mockHostForAccessList) AddAddressToAccessList(addr types.Address) {
+	m.addrs[addr] = struct{}{}
+}
+
+func (m *mockHostForAccessList) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	m.AddAddressToAccessList(addr)
+	m.slots[slot] = struct{}{}
+}
+
+func (m *mockHostForAccessList) AccountExists(types.Address) bool {
+	return true
+}
+
+func (m *mockHostForAccessList) Empty(types.Address) bool {
+	return false
+}
+
+func (m *mockHostForAccessList) GetBalance(types.Address) *big.Int {
+	return big.NewInt(0)
+}
+
+func (m *mockHostForAccessList) GetCodeSize(types.Address) int {
+	return 0
+}
+
+func (m *mockHostForAccessList) GetCodeHash(types.Address) types.Hash {
+	return types.Hash{}
+}
+
+func (m *mockHostForAccessList) GetCode(types.Address) []byte {
+	return nil
+}
+
+func (m *mockHostForAccessList) GetStorage(types.Address, types.Hash) types.Hash {
+	return types.Hash{}
+}
+
+func (m *mockHostForAccessList) SetStorage(types.Address, types.Hash, types.Hash, *runtime.ForksInTime) runtime.StorageStatus {
+	return m.status
+}
+
+func (m *mockHostForAccessList) Selfdestruct(types.Address, types.Address) {
+}
+
+func (m *mockHostForAccessList) Callx(c *runtime.Contract, _ runtime.Host) *runtime.ExecutionResult {
+	return &runtime.ExecutionResult{GasLeft: c.Gas}
+}
+
+func TestAccessListGas(t *testing.T) {
+	berlin := &runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+	}
+
+	target := big.NewInt(0xa1)
+
+	// the arguments are pushed in order, the last one is on top of the stack
+	cases := []struct {
+		op     OpCode
+		args   []*big.Int
+		status runtime.StorageStatus
+		cold   uint64
+		warm   uint64
+	}{
+		{op: BALANCE, args: []*big.Int{target}, cold: 2600, warm: 100},
+		{op: EXTCODESIZE, args: []*big.Int{target}, cold: 2600, warm: 100},
+		{op: EXTCODEHASH, args: []*big.Int{target}, cold: 2600, warm: 100},
+		// size, code offset, memory offset, address
+		{op: EXTCODECOPY, args: []*big.Int{zero, zero, zero, target}, cold: 2600, warm: 100},
+		// return size, return offset, input size, input offset, value, address, gas
+		{op: CALL, args: []*big.Int{zero, zero, zero, zero, zero, target, zero}, cold: 2600, warm: 100},
+		{op: CALLCODE, args: []*big.Int{zero, zero, zero, zero, zero, target, zero}, cold: 2600, warm: 100},
+		// return size, return offset, input size, input offset, address, gas
+		{op: DELEGATECALL, args: []*big.Int{zero, zero, zero, zero, target, zero}, cold: 2600, warm: 100},
+		{op: STATICCALL, args: []*big.Int{zero, zero, zero, zero, target, zero}, cold: 2600, warm: 100},
+		// the slot is accessed by the contract itself
+		{op: SLOAD, args: []*big.Int{one}, cold: 2100, warm: 100},
+		// value, slot
+		{op: SSTORE, args: []*big.Int{one, one}, status: runtime.StorageUnchanged, cold: 2100 + 100, warm: 100},
+		{op: SSTORE, args: []*big.Int{one, one}, status: runtime.StorageModified, cold: 2100 + 2900, warm: 2900},
+		{op: SSTORE, args: []*big.Int{one, one}, status: runtime.StorageAdded, cold: 2100 + 20000, warm: 20000},
+		// the beneficiary of the selfdestruct
+		{op: SELFDESTRUCT, args: []*big.Int{target}, cold: 5000 + 2600, warm: 5000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.op.String(), func(t *testing.T) {
+			host := newMockHostForAccessList()
+			host.status = c.status
+
+			run := func() uint64 {
+				s, close := getState()
+				defer close()
+
+				s.host = host
+				s.msg = &runtime.Contract{Address: addr1}
+				for _, arg := range c.args {
+					s.push(new(big.Int).Set(arg))
+				}
+
+				s.gas = 100000
+				assert.NoError(t, runOp(s, berlin, c.op))
+				return 100000 - s.gas
+			}
+
+			assert.Equal(t, c.cold, run(), "cold")
+			assert.Equal(t, c.warm, run(), "warm")
+		})
+	}
+
+	// before berlin there is no access list
+	s, close := getState()
+	defer close()
+
+	s.host = newMockHostForAccessList()
+	s.push(target)
+	s.gas = 100000
+	assert.NoError(t, runOp(s, &runtime.ForksInTime{Homestead: true, EIP150: true, EIP158: true, Byzantium: true, Constantinople: true, Petersburg: true, Istanbul: true}, BALANCE))
+	assert.Equal(t, uint64(100000-700), s.gas)
+}
@@ -0,0 +1,90 @@
+package evm
+
+import (
+	"math/big"
+)
+
+// memorySizeFunc returns the offset and size of the memory area accessed
+// by an instruction
+type memorySizeFunc func(c *state) (offset, size *big.Int)
+
+func memoryMLoad(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), wordSize
+}
+
+func memoryMStore(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), wordSize
+}
+
+func memoryMStore8(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), one
+}
+
+func memorySha3(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), c.peekAt(2)
+}
+
+// memoryCopy is the memory area of the copy instructions with the memory
+// offset as first argument and the size as third argument
+func memoryCopy(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), c.peekAt(3)
+}
+
+func memoryExtCodeCopy(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(2), c.peekAt(4)
+}
+
+// memoryMCopy is the area that ends last between the source and the
+// destination (eip-5656)
+func memoryMCopy(c *state) (*big.Int, *big.Int) {
+	dst, src := c.peekAt(1), c.peekAt(2)
+	if dst.Cmp(src) >= 0 {
+		return dst, c.peekAt(3)
+	}
+	return src, c.peekAt(3)
+}
+
+func memoryLog(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), c.peekAt(2)
+}
+
+func memoryReturn(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(1), c.peekAt(2)
+}
+
+func memoryCreate(c *state) (*big.Int, *big.Int) {
+	return c.peekAt(2), c.peekAt(3)
+}
+
+func memoryCall(c *state) (*big.Int, *big.Int) {
+	return c.callMemoryArea(4)
+}
+
+// memoryDelegateCall is the memory area of the calls without value
+func memoryDelegateCall(c *state) (*big.Int, *big.Int) {
+	return c.callMemoryArea(3)
+}
+
+// callMemoryArea returns the area that ends last between the input and the
+// output of a call, with the input offset at the given stack position
+func (c *state) callMemoryArea(n int) (*big.Int, *big.Int) {
+	inOffset, inSize := c.peekAt(n), c.peekAt(n+1)
+	retOffset, retSize := c.peekAt(n+2), c.peekAt(n+3)
+
+	if retSize.Sign() == 0 {
+		return inOffset, inSize
+	}
+	if inSize.Sign() == 0 {
+		return retOffset, retSize
+	}
+
+	inEnd := acquireBig().Add(inOffset, inSize)
+	retEnd := acquireBig().Add(retOffset, retSize)
+	defer releaseBig(inEnd)
+	defer releaseBig(retEnd)
+
+	if inEnd.Cmp(retEnd) >= 0 {
+		return inOffset, inSize
+	}
+	return retOffset, retSize
+}
@@ -86,6 +86,9 @@ const (
 	// SAR performs an arithmetic shift right
 	SAR = 0x1D
 
+	// CLZ counts the leading zero bits of a (u)int256
+	CLZ = 0x1E
+
 	// SHA3 performs the keccak256 hash function
 	SHA3 = 0x20
 
@@ -152,6 +155,9 @@ const (
 	// DIFFICULTY returns the current block's difficulty
 	DIFFICULTY = 0x44
 
+	// PREVRANDAO returns the randomness of the previous block after the merge
+	PREVRANDAO = DIFFICULTY
+
 	// GASLIMIT returns the current block's gas limit
 	GASLIMIT = 0x45
 
@@ -161,6 +167,15 @@ const (
 	// SELFBALANCE returns the balance of the current account
 	SELFBALANCE = 0x47
 
+	// BASEFEE returns the base fee of the current block
+	BASEFEE = 0x48
+
+	// BLOBHASH returns the versioned hash of a blob of the transaction
+	BLOBHASH = 0x49
+
+	// BLOBBASEFEE returns the blob base fee of the current block
+	BLOBBASEFEE = 0x4A
+
 	// POP pops a (u)int256 off the stack and discards it
 	POP = 0x50
 
@@ -197,6 +212,18 @@ const (
 	// JUMPDEST corresponds to a possible jump destination
 	JUMPDEST = 0x5B
 
+	// TLOAD reads a (u)int256 from transient storage
+	TLOAD = 0x5C
+
+	// TSTORE writes a (u)int256 to transient storage
+	TSTORE = 0x5D
+
+	// MCOPY copies memory areas
+	MCOPY = 0x5E
+
+	// PUSH0 pushes a zero value onto the stack
+	PUSH0 = 0x5F
+
 	// PUSH1 pushes a 1-byte value onto the stack
 	PUSH1 = 0x60
 
@@ -283,6 +310,7 @@ var opCodeToString = map[OpCode]string{
 	SHL:            "SHL",
 	SHR:            "SHR",
 	SAR:            "SAR",
+	CLZ:            "CLZ",
 	ADDMOD:         "ADDMOD",
 	MULMOD:         "MULMOD",
 	SHA3:           "SHA3",
@@ -320,6 +348,10 @@ var opCodeToString = map[OpCode]string{
 	MSIZE:          "MSIZE",
 	GAS:            "GAS",
 	JUMPDEST:       "JUMPDEST",
+	TLOAD:          "TLOAD",
+	TSTORE:         "TSTORE",
+	MCOPY:          "MCOPY",
+	PUSH0:          "PUSH0",
 	CREATE:         "CREATE",
 	CALL:           "CALL",
 	RETURN:         "RETURN",
@@ -331,6 +363,9 @@ var opCodeToString = map[OpCode]string{
 	SELFDESTRUCT:   "SELFDESTRUCT",
 	CHAINID:        "CHAINID",
 	SELFBALANCE:    "SELFBALANCE",
+	BASEFEE:        "BASEFEE",
+	BLOBHASH:       "BLOBHASH",
+	BLOBBASEFEE:    "BLOBBASEFEE",
 }
 
 func opCodesToString(from, to OpCode, str string) {
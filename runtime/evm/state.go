@@ -27,8 +27,6 @@ func releaseState(s *state) {
 	statePool.Put(s)
 }
 
-const stackSize = 1024
-
 var (
 	errOutOfGas              = runtime.ErrOutOfGas
 	errStackUnderflow        = runtime.ErrStackUnderflow
@@ -52,6 +50,12 @@ type state struct {
 	msg    *runtime.Contract // change with msg
 	config *runtime.ForksInTime
 
+	// instructions are the instructions enabled in the forks of config
+	instructions *instructionSet
+
+	// limits are the protocol limits of the chain
+	limits runtime.Limits
+
 	// memory
 	memory      []byte
 	lastGasCost uint64
@@ -205,7 +209,7 @@ func (c *state) Run() ([]byte, error) {
 
 		op := OpCode(c.code[c.ip])
 
-		inst := dispatchTable[op]
+		inst := &c.instructions[op]
 		if inst.inst == nil {
 			c.exit(errOpCodeNotFound)
 			break
@@ -220,12 +224,24 @@ func (c *state) Run() ([]byte, error) {
 			c.exit(errOutOfGas)
 			break
 		}
+		// expand the memory before the dynamic gas since it depends on
+		// the checked sizes of the memory areas
+		if inst.memorySize != nil {
+			if !c.checkMemory(inst.memorySize(c)) {
+				break
+			}
+		}
+		if inst.dynamicGas != nil {
+			if !c.consumeGas(inst.dynamicGas(c)) {
+				break
+			}
+		}
 
 		// execute the instruction
 		inst.inst(c)
 
 		// check if stack size exceeds the max size
-		if c.sp > stackSize {
+		if c.sp > c.limits.StackLimit {
 			c.exit(errStackOverflow)
 			break
 		}
@@ -246,6 +262,10 @@ func bigToHash(b *big.Int) types.Hash {
 	return types.BytesToHash(b.Bytes())
 }
 
+func bigToAddress(b *big.Int) types.Address {
+	return types.BytesToAddress(b.Bytes())
+}
+
 func (c *state) Len() int {
 	return len(c.memory)
 }
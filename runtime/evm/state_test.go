@@ -3,6 +3,7 @@ package evm
 import (
 	"testing"
 
+	"github.com/0xPolygon/eth-state-transition/runtime"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,7 +45,7 @@ func TestStackTop(t *testing.T) {
 
 func TestStackOverflow(t *testing.T) {
 	code := codeHelper{}
-	for i := 0; i < stackSize; i++ {
+	for i := 0; i < runtime.MainnetLimits.StackLimit; i++ {
 		code.push1()
 	}
 
@@ -53,6 +54,8 @@ func TestStackOverflow(t *testing.T) {
 
 	s.code = code.buf
 	s.gas = 10000
+	s.instructions = &frontierInstructionSet
+	s.limits = runtime.MainnetLimits
 
 	_, err := s.Run()
 	assert.NoError(t, err)
@@ -63,6 +66,18 @@ func TestStackOverflow(t *testing.T) {
 	s.reset()
 	s.code = code.buf
 	s.gas = 10000
+	s.instructions = &frontierInstructionSet
+	s.limits = runtime.MainnetLimits
+
+	_, err = s.Run()
+	assert.Equal(t, errStackOverflow, err)
+
+	// the chain lowers the stack limit
+	s.reset()
+	s.code = code.buf[:2*11]
+	s.gas = 10000
+	s.instructions = &frontierInstructionSet
+	s.limits.StackLimit = 10
 
 	_, err = s.Run()
 	assert.Equal(t, errStackOverflow, err)
@@ -82,6 +97,8 @@ func TestStackUnderflow(t *testing.T) {
 
 	s.code = code.buf
 	s.gas = 10000
+	s.instructions = &frontierInstructionSet
+	s.limits = runtime.MainnetLimits
 
 	_, err := s.Run()
 	assert.NoError(t, err)
@@ -91,6 +108,8 @@ func TestStackUnderflow(t *testing.T) {
 	s.reset()
 	s.code = code.buf
 	s.gas = 10000
+	s.instructions = &frontierInstructionSet
+	s.limits = runtime.MainnetLimits
 
 	_, err = s.Run()
 	assert.Equal(t, errStackUnderflow, err)
@@ -102,6 +121,8 @@ func TestOpcodeNotFound(t *testing.T) {
 
 	s.code = []byte{0xA5}
 	s.gas = 1000
+	s.instructions = &frontierInstructionSet
+	s.limits = runtime.MainnetLimits
 
 	_, err := s.Run()
 	assert.Equal(t, errOpCodeNotFound, err)
@@ -0,0 +1,364 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/0xPolygon/eth-state-transition/types"
+)
+
+// genesisIgnoredFields are the fields of the genesis config that do not
+// change the state transition
+var genesisIgnoredFields = map[string]struct{}{
+	"eip150Hash":                    {},
+	"muirGlacierBlock":              {},
+	"arrowGlacierBlock":             {},
+	"grayGlacierBlock":              {},
+	"terminalTotalDifficultyPassed": {},
+	"ethash":                        {},
+	"clique":                        {},
+}
+
+// genesisUnsupportedForks are the forks of the genesis config that are not
+// implemented. They are only an error if they are scheduled.
+var genesisUnsupportedForks = map[string]struct{}{
+	"amsterdamTime":         {},
+	"bogotaTime":            {},
+	"verkleTime":            {},
+	"ubtTime":               {},
+	"enableVerkleAtGenesis": {},
+	"enableUBTAtGenesis":    {},
+}
+
+// genesisParisBlocks are the paris blocks of the chains whose genesis config
+// has the terminal total difficulty but not the mergeNetsplitBlock
+var genesisParisBlocks = map[int]struct {
+	ttd   string
+	block uint64
+}{
+	1: {"58750000000000000000000", 15537394},
+}
+
+// ParseGenesis returns the params of the config object of a geth
+// genesis.json file
+func ParseGenesis(data []byte) (*Params, error) {
+	var genesis struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, err
+	}
+	if len(genesis.Config) == 0 {
+		return nil, fmt.Errorf("genesis config not found")
+	}
+	return ParseGenesisConfig(genesis.Config)
+}
+
+// ParseGenesisConfig returns the params of a geth genesis config object.
+// Paris is scheduled at the mergeNetsplitBlock, at genesis if the terminal
+// total difficulty is zero or at the known paris block of the chain. The
+// blob schedule sets the blob params of the forks. The DAO fork and the
+// block allocs of bor are irregular changes. Any unknown field or scheduled
+// fork that is not implemented is an error.
+func ParseGenesisConfig(data []byte) (*Params, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	forks := &Forks{}
+	p := &Params{
+		Forks: forks,
+	}
+
+	// the forks after london are scheduled by block in bor
+	forkFields := map[string]**Fork{
+		"homesteadBlock":      &forks.Homestead,
+		"eip150Block":         &forks.EIP150,
+		"eip155Block":         &forks.EIP155,
+		"eip158Block":         &forks.EIP158,
+		"byzantiumBlock":      &forks.Byzantium,
+		"constantinopleBlock": &forks.Constantinople,
+		"petersburgBlock":     &forks.Petersburg,
+		"istanbulBlock":       &forks.Istanbul,
+		"berlinBlock":         &forks.Berlin,
+		"londonBlock":         &forks.London,
+		"mergeNetsplitBlock":  &forks.Paris,
+		"shanghaiBlock":       &forks.Shanghai,
+		"cancunBlock":         &forks.Cancun,
+		"pragueBlock":         &forks.Prague,
+		"osakaBlock":          &forks.Osaka,
+		"shanghaiTime":        &forks.ShanghaiTime,
+		"cancunTime":          &forks.CancunTime,
+		"pragueTime":          &forks.PragueTime,
+		"osakaTime":           &forks.OsakaTime,
+		"bpo1Time":            &forks.BPO1Time,
+		"bpo2Time":            &forks.BPO2Time,
+		"bpo3Time":            &forks.BPO3Time,
+		"bpo4Time":            &forks.BPO4Time,
+		"bpo5Time":            &forks.BPO5Time,
+	}
+
+	// sort the fields to always report the same error
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ttd *big.Int
+	var daoForkBlock *uint64
+	var daoForkSupport bool
+	for _, name := range names {
+		value := fields[name]
+
+		if fork, ok := forkFields[name]; ok {
+			if err := json.Unmarshal(value, fork); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %v", name, err)
+			}
+			continue
+		}
+		if _, ok := genesisIgnoredFields[name]; ok {
+			continue
+		}
+		if _, ok := genesisUnsupportedForks[name]; ok {
+			if v := string(value); v != "null" && v != "false" {
+				return nil, fmt.Errorf("fork %s is not supported", name)
+			}
+			continue
+		}
+
+		var err error
+		switch name {
+		case "chainId":
+			err = json.Unmarshal(value, &p.ChainID)
+		case "terminalTotalDifficulty":
+			err = json.Unmarshal(value, &ttd)
+		case "depositContractAddress":
+			p.DepositContract = &types.Address{}
+			err = json.Unmarshal(value, p.DepositContract)
+		case "daoForkBlock":
+			err = json.Unmarshal(value, &daoForkBlock)
+		case "daoForkSupport":
+			err = json.Unmarshal(value, &daoForkSupport)
+		case "blobSchedule":
+			err = parseBlobSchedule(value, p)
+		case "bor":
+			err = parseBorConfig(value, p)
+		default:
+			return nil, fmt.Errorf("unknown genesis config field %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", name, err)
+		}
+	}
+
+	if forks.Paris == nil && ttd != nil {
+		if ttd.Sign() == 0 {
+			forks.Paris = NewFork(0)
+		} else if paris, ok := genesisParisBlocks[p.ChainID]; ok && paris.ttd == ttd.String() {
+			forks.Paris = NewFork(paris.block)
+		} else {
+			return nil, fmt.Errorf("paris block unknown for terminal total difficulty %s without mergeNetsplitBlock", ttd)
+		}
+	}
+
+	// the chains that did not support the DAO fork keep the balances
+	if daoForkBlock != nil && daoForkSupport {
+		p.Irregular = append(p.Irregular, NewDAOForkChange(*daoForkBlock))
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseBlobSchedule sets the blob schedule of the params. The forks of the
+// schedule that are not implemented are an error.
+func parseBlobSchedule(data []byte, p *Params) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	p.BlobSchedule = &BlobSchedule{}
+	return dec.Decode(p.BlobSchedule)
+}
+
+// borIgnoredFields are the fields of the bor config object that only change
+// the consensus engine of bor, like the validators, the sprints or the state
+// syncs, and not the state transition of the transactions
+var borIgnoredFields = map[string]struct{}{
+	"period":                          {},
+	"producerDelay":                   {},
+	"sprint":                          {},
+	"backupMultiplier":                {},
+	"validatorContract":               {},
+	"stateReceiverContract":           {},
+	"overrideStateSyncRecords":        {},
+	"overrideStateSyncRecordsInRange": {},
+	"stateSyncConfirmationDelay":      {},
+	"skipValidatorByteCheck":          {},
+	"jaipurBlock":                     {},
+	"delhiBlock":                      {},
+	"indoreBlock":                     {},
+}
+
+// borUnsupportedForks are the forks of bor that change the state transition
+// and are not implemented. They are only an error if they are scheduled.
+var borUnsupportedForks = map[string]struct{}{
+	"bhilaiBlock": {},
+	"rioBlock":    {},
+}
+
+// borDisabledEIPs are the eips of the forks that depend on the beacon
+// chain, which bor does not have
+var borDisabledEIPs = []EIP{
+	EIP4788,
+	EIP4844,
+	EIP6110,
+	EIP7002,
+	EIP7251,
+	EIP7685,
+}
+
+// borAhmedabadLimits are the limits of bor after ahmedabad, which raises
+// the code size limit (pip-30)
+func borAhmedabadLimits() Limits {
+	limits := MainnetLimits
+	limits.MaxCodeSize = 32768
+	limits.MaxInitCodeSize = 2 * 32768
+	return limits
+}
+
+// parseBorConfig adds the fields of the bor config object to the params.
+// The block allocs are irregular changes. Bor replaces the code of the
+// accounts at the end of the block, so the changes are applied at the end
+// of their block. The balance and the storage of the allocs are not
+// changed. The burnt contracts receive the base fee, the napoli fork
+// enables the secp256r1 precompile (rip-7212) and ahmedabad raises the code
+// size limit. The eips of the beacon chain are disabled. Any unknown field
+// or scheduled fork that is not implemented is an error.
+func parseBorConfig(data []byte, p *Params) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if p.EIPs == nil {
+		p.EIPs = map[EIP]*Fork{}
+	}
+	for _, eip := range borDisabledEIPs {
+		p.EIPs[eip] = nil
+	}
+
+	// sort the fields to always report the same error
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := fields[name]
+
+		if _, ok := borIgnoredFields[name]; ok {
+			continue
+		}
+		if _, ok := borUnsupportedForks[name]; ok {
+			if string(value) != "null" {
+				return fmt.Errorf("bor fork %s is not supported", name)
+			}
+			continue
+		}
+
+		var err error
+		switch name {
+		case "blockAlloc":
+			err = parseBorBlockAlloc(value, p)
+		case "burntContract":
+			err = parseBorBurntContract(value, p)
+		case "napoliBlock":
+			var napoli *Fork
+			if err = json.Unmarshal(value, &napoli); err == nil && napoli != nil {
+				p.EIPs[RIP7212] = napoli
+			}
+		case "ahmedabadBlock":
+			var ahmedabad *Fork
+			if err = json.Unmarshal(value, &ahmedabad); err == nil && ahmedabad != nil {
+				if p.Limits == nil {
+					p.Limits = map[uint64]Limits{}
+				}
+				p.Limits[uint64(*ahmedabad)] = borAhmedabadLimits()
+			}
+		default:
+			return fmt.Errorf("unknown bor config field %s", name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// parseBorBurntContract sets the burnt contracts of the params by block
+func parseBorBurntContract(data []byte, p *Params) error {
+	var contracts map[string]types.Address
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		return err
+	}
+
+	p.BurntContract = make(map[uint64]types.Address, len(contracts))
+	for key, addr := range contracts {
+		block, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid burnt contract block number %s", key)
+		}
+		p.BurntContract[block] = addr
+	}
+	return nil
+}
+
+// parseBorBlockAlloc adds the code of the block allocs to the irregular
+// changes of the params
+func parseBorBlockAlloc(data []byte, p *Params) error {
+	var blockAlloc map[string]map[types.Address]*AccountOverride
+	if err := json.Unmarshal(data, &blockAlloc); err != nil {
+		return err
+	}
+
+	blocks := make([]uint64, 0, len(blockAlloc))
+	allocs := make(map[uint64]map[types.Address]*AccountOverride, len(blockAlloc))
+	for key, alloc := range blockAlloc {
+		block, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid block alloc number %s", key)
+		}
+		blocks = append(blocks, block)
+		allocs[block] = alloc
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i] < blocks[j]
+	})
+
+	for _, block := range blocks {
+		change := &IrregularChange{
+			Block:    block,
+			AtEnd:    true,
+			Accounts: map[types.Address]*AccountOverride{},
+		}
+		for addr, account := range allocs[block] {
+			// an alloc without code removes the code of the account
+			code := account.Code
+			if code == nil {
+				code = []byte{}
+			}
+			change.Accounts[addr] = &AccountOverride{Code: code}
+		}
+		p.Irregular = append(p.Irregular, change)
+	}
+	return nil
+}
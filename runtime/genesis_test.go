@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGenesis(t *testing.T) {
+	config, err := ioutil.ReadFile("testdata/hoodi.json")
+	assert.NoError(t, err)
+
+	data := `{"config": ` + string(config) + `, "alloc": {}}`
+	p, err := ParseGenesis([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, HoodiParams, p)
+}
+
+// TestParseGenesisChains parses the genesis configs of geth for the known
+// chains
+func TestParseGenesisChains(t *testing.T) {
+	// the paris block of mainnet is known from the terminal total difficulty
+	data, err := ioutil.ReadFile("testdata/mainnet.json")
+	assert.NoError(t, err)
+
+	p, err := ParseGenesisConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, MainnetParams, p)
+
+	data, err = ioutil.ReadFile("testdata/sepolia.json")
+	assert.NoError(t, err)
+
+	// amsterdam is not implemented
+	_, err = ParseGenesisConfig(data)
+	assert.EqualError(t, err, "fork amsterdamTime is not supported")
+
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	delete(fields, "amsterdamTime")
+	data, err = json.Marshal(fields)
+	assert.NoError(t, err)
+
+	p, err = ParseGenesisConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, SepoliaParams, p)
+}
+
+func TestParseGenesisConfig(t *testing.T) {
+	// paris at genesis with a zero terminal total difficulty
+	p, err := ParseGenesisConfig([]byte(`{"chainId": 17000, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 0, "londonBlock": 0, "terminalTotalDifficulty": 0, "shanghaiTime": 1696000704}`))
+	assert.NoError(t, err)
+	assert.Equal(t, NewFork(0), p.Forks.Paris)
+	assert.Equal(t, NewFork(1696000704), p.Forks.ShanghaiTime)
+
+	// bor schedules the forks after london by block
+	p, err = ParseGenesisConfig([]byte(`{"chainId": 80002, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 0, "londonBlock": 73100, "shanghaiBlock": 73100, "cancunBlock": 5423600, "bor": {"period": {"0": 2}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 80002, p.ChainID)
+	assert.Nil(t, p.Forks.Paris)
+	assert.Equal(t, NewFork(5423600), p.Forks.Cancun)
+
+	cases := []string{
+		// unknown field
+		`{"chainId": 1, "homesteadBlock": 0, "fooBlock": 10}`,
+		// scheduled fork that is not supported
+		`{"chainId": 1, "homesteadBlock": 0, "amsterdamTime": 10}`,
+		// blob params of a fork that is not supported
+		`{"chainId": 1, "homesteadBlock": 0, "blobSchedule": {"amsterdam": {"target": 1, "max": 2, "baseFeeUpdateFraction": 3}}}`,
+		// blob parameter only fork without blob params
+		`{"chainId": 1, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 0, "londonBlock": 0, "terminalTotalDifficulty": 0, "shanghaiTime": 0, "cancunTime": 0, "pragueTime": 0, "osakaTime": 0, "bpo1Time": 10}`,
+		// block alloc with an invalid number
+		`{"chainId": 137, "homesteadBlock": 0, "bor": {"blockAlloc": {"0x10": {}}}}`,
+		// unknown bor field
+		`{"chainId": 137, "homesteadBlock": 0, "bor": {"fooBlock": 10}}`,
+		// scheduled bor fork that is not supported
+		`{"chainId": 137, "homesteadBlock": 0, "bor": {"bhilaiBlock": 10}}`,
+		// paris block cannot be known from the terminal total difficulty
+		`{"chainId": 1, "homesteadBlock": 0, "terminalTotalDifficulty": 100}`,
+		// forks out of order
+		`{"chainId": 1, "homesteadBlock": 10, "eip150Block": 5}`,
+	}
+	for _, c := range cases {
+		_, err := ParseGenesisConfig([]byte(c))
+		assert.Error(t, err, c)
+	}
+
+	// unscheduled forks that are not supported are allowed
+	_, err = ParseGenesisConfig([]byte(`{"chainId": 1, "homesteadBlock": 0, "daoForkBlock": null, "enableVerkleAtGenesis": false}`))
+	assert.NoError(t, err)
+}
+
+func TestParseGenesisIrregular(t *testing.T) {
+	// the DAO fork drains the DAO accounts only if supported
+	p, err := ParseGenesisConfig([]byte(`{"chainId": 1, "homesteadBlock": 1150000, "daoForkBlock": 1920000, "daoForkSupport": true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*IrregularChange{NewDAOForkChange(1920000)}, p.Irregular)
+	assert.Len(t, p.Irregular[0].Moves[0].From, 116)
+
+	p, err = ParseGenesisConfig([]byte(`{"chainId": 61, "homesteadBlock": 1150000, "daoForkBlock": 1920000, "daoForkSupport": false}`))
+	assert.NoError(t, err)
+	assert.Empty(t, p.Irregular)
+
+	// the code of the block allocs of bor is replaced at the end of the block
+	p, err = ParseGenesisConfig([]byte(`{"chainId": 137, "homesteadBlock": 0, "bor": {"period": {"0": 2}, "blockAlloc": {"200": {"0000000000000000000000000000000000001010": {"balance": "0x0", "code": "0x6001"}}, "100": {"0x0000000000000000000000000000000000001001": {"balance": "0x10"}}}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*IrregularChange{
+		{
+			Block: 100,
+			AtEnd: true,
+			Accounts: map[types.Address]*AccountOverride{
+				types.StringToAddress("1001"): {Code: []byte{}},
+			},
+		},
+		{
+			Block: 200,
+			AtEnd: true,
+			Accounts: map[types.Address]*AccountOverride{
+				types.StringToAddress("1010"): {Code: []byte{0x60, 0x01}},
+			},
+		},
+	}, p.Irregular)
+}
+
+func TestParseGenesisBor(t *testing.T) {
+	p, err := ParseGenesisConfig([]byte(`{"chainId": 137, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0, "eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0, "istanbulBlock": 0, "berlinBlock": 0, "londonBlock": 0, "shanghaiBlock": 0, "cancunBlock": 0, "pragueBlock": 0, "bor": {"sprint": {"0": 64}, "burntContract": {"100": "0x00000000000000000000000000000000000000b1", "200": "0x00000000000000000000000000000000000000b2"}, "napoliBlock": 150, "ahmedabadBlock": 300, "bhilaiBlock": null}}`))
+	assert.NoError(t, err)
+
+	// the latest burnt contract not after the block receives the base fee
+	assert.Nil(t, p.At(99, 0).BurntContract)
+	assert.Equal(t, types.StringToAddress("b1"), *p.At(199, 0).BurntContract)
+	assert.Equal(t, types.StringToAddress("b2"), *p.At(200, 0).BurntContract)
+
+	// napoli enables the secp256r1 precompile
+	forks := p.At(149, 0)
+	assert.False(t, forks.Enabled(RIP7212))
+	forks = p.At(150, 0)
+	assert.True(t, forks.Enabled(RIP7212))
+
+	// bor does not have the eips of the beacon chain
+	assert.True(t, forks.Prague)
+	assert.True(t, forks.Enabled(EIP7702))
+	assert.False(t, forks.Enabled(EIP4788))
+	assert.False(t, forks.Enabled(EIP4844))
+	assert.False(t, forks.Enabled(EIP7685))
+
+	// ahmedabad raises the code size limit (pip-30)
+	forks = p.At(299, 0)
+	assert.Equal(t, MainnetLimits, forks.GetLimits())
+	forks = p.At(300, 0)
+	limits := forks.GetLimits()
+	assert.Equal(t, 32768, limits.MaxCodeSize)
+	assert.Equal(t, 65536, limits.MaxInitCodeSize)
+}
+
+func TestPresetsValidate(t *testing.T) {
+	for name, p := range Presets {
+		assert.NoError(t, p.Validate(), name)
+	}
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/eth-state-transition/helper"
+	"github.com/0xPolygon/eth-state-transition/types"
+)
+
+// IrregularChange is a state change mandated by the consensus of the chain
+// that is not a transaction, like the drain of the DAO accounts in mainnet.
+// It is applied before the first transaction of the block or, if AtEnd is
+// set, after the last one.
+type IrregularChange struct {
+	// Block is the number of the block of the change
+	Block uint64 `json:"block"`
+
+	// AtEnd applies the change at the end of the block
+	AtEnd bool `json:"atEnd,omitempty"`
+
+	// Moves are the balances moved between accounts. They are applied
+	// before the accounts.
+	Moves []*BalanceMove `json:"moves,omitempty"`
+
+	// Accounts are the accounts whose code, balance or storage is replaced
+	Accounts map[types.Address]*AccountOverride `json:"accounts,omitempty"`
+}
+
+// BalanceMove moves the whole balance of the accounts to the recipient
+type BalanceMove struct {
+	From []types.Address `json:"from"`
+	To   types.Address   `json:"to"`
+}
+
+// AccountOverride replaces the fields of an account that are set. The
+// storage slots that are not set keep their value.
+type AccountOverride struct {
+	Code    []byte                    `json:"code,omitempty"`
+	Balance *big.Int                  `json:"balance,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+type accountOverrideJSON struct {
+	Code    *string                   `json:"code,omitempty"`
+	Balance *string                   `json:"balance,omitempty"`
+	Storage map[types.Hash]types.Hash `json:"storage,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (a *AccountOverride) MarshalJSON() ([]byte, error) {
+	enc := accountOverrideJSON{
+		Storage: a.Storage,
+	}
+	if a.Code != nil {
+		code := helper.EncodeToHex(a.Code)
+		enc.Code = &code
+	}
+	if a.Balance != nil {
+		balance := "0x" + a.Balance.Text(16)
+		enc.Balance = &balance
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The code is
+// in hex and the balance either in decimal or in hex.
+func (a *AccountOverride) UnmarshalJSON(data []byte) error {
+	var dec accountOverrideJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+
+	var err error
+	if dec.Code != nil {
+		if a.Code, err = helper.ParseBytes(dec.Code); err != nil {
+			return fmt.Errorf("failed to decode code: %v", err)
+		}
+	}
+	if a.Balance, err = helper.ParseUint256orHex(dec.Balance); err != nil {
+		return fmt.Errorf("failed to decode balance: %v", err)
+	}
+	a.Storage = dec.Storage
+	return nil
+}
+
+// IrregularAt returns the irregular changes of the given block number
+func (p *Params) IrregularAt(block uint64) []*IrregularChange {
+	var changes []*IrregularChange
+	for _, change := range p.Irregular {
+		if change.Block == block {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIrregularChangeJSON(t *testing.T) {
+	data := `{
+		"forks": {"homestead": 0},
+		"irregular": [
+			{
+				"block": 10,
+				"moves": [{"from": ["0x0000000000000000000000000000000000000001"], "to": "0x0000000000000000000000000000000000000002"}],
+				"accounts": {
+					"0x0000000000000000000000000000000000000003": {
+						"code": "0x6001",
+						"balance": "100",
+						"storage": {"0x01": "0x02"}
+					}
+				}
+			}
+		]
+	}`
+
+	var p Params
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	account := p.Irregular[0].Accounts[types.StringToAddress("3")]
+	assert.Equal(t, []byte{0x60, 0x01}, account.Code)
+	assert.Equal(t, big.NewInt(100), account.Balance)
+	assert.Equal(t, types.StringToHash("2"), account.Storage[types.StringToHash("1")])
+
+	// only the changes of the block are enabled
+	forks := p.At(9, 0)
+	assert.Empty(t, forks.Irregular)
+	forks = p.At(10, 0)
+	assert.Equal(t, p.Irregular, forks.Irregular)
+
+	out, err := json.Marshal(&p)
+	assert.NoError(t, err)
+
+	var p2 Params
+	assert.NoError(t, json.Unmarshal(out, &p2))
+	assert.Equal(t, p.Irregular, p2.Irregular)
+}
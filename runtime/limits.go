@@ -0,0 +1,44 @@
+package runtime
+
+import "encoding/json"
+
+// Limits are the protocol limits of a chain
+type Limits struct {
+	// MaxCodeSize is the maximum size of the code of a contract (eip-170)
+	MaxCodeSize int `json:"maxCodeSize"`
+
+	// MaxInitCodeSize is the maximum size of the initcode of a contract
+	// creation (eip-3860)
+	MaxInitCodeSize int `json:"maxInitCodeSize"`
+
+	// MaxCallDepth is the maximum depth of the nested calls and creations
+	MaxCallDepth int `json:"maxCallDepth"`
+
+	// StackLimit is the maximum number of items in the stack of the evm
+	StackLimit int `json:"stackLimit"`
+
+	// CodeDepositGas is the gas paid per byte of the code of a new contract
+	CodeDepositGas uint64 `json:"codeDepositGas"`
+}
+
+// MainnetLimits are the protocol limits of the ethereum mainnet
+var MainnetLimits = Limits{
+	MaxCodeSize:     24576,
+	MaxInitCodeSize: 2 * 24576,
+	MaxCallDepth:    1024,
+	StackLimit:      1024,
+	CodeDepositGas:  200,
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The fields
+// that are not set keep the mainnet limits.
+func (l *Limits) UnmarshalJSON(data []byte) error {
+	type limits Limits
+
+	ll := limits(MainnetLimits)
+	if err := json.Unmarshal(data, &ll); err != nil {
+		return err
+	}
+	*l = Limits(ll)
+	return nil
+}
@@ -1,25 +1,145 @@
 package runtime
 
 import (
+	"fmt"
 	"math/big"
+
+	"github.com/0xPolygon/eth-state-transition/types"
 )
 
 // Params are all the set of params for the chain
 type Params struct {
 	Forks   *Forks `json:"forks"`
 	ChainID int    `json:"chainID"`
+
+	// EIPs overrides the activation of individual eips by block number
+	// regardless of the forks. A nil fork disables the eip.
+	EIPs map[EIP]*Fork `json:"eips,omitempty"`
+
+	// EIPsTime overrides the activation of individual eips by timestamp
+	// like EIPs. An eip cannot be overridden both by block and by timestamp.
+	EIPsTime map[EIP]*Fork `json:"eipsTime,omitempty"`
+
+	// DepositContract is the address of the deposit contract (eip-6110)
+	// to set in the transition. If nil the mainnet contract is used.
+	DepositContract *types.Address `json:"depositContract,omitempty"`
+
+	// Limits are the protocol limits of the chain by the block they are
+	// set from. The latest limits not after the block are used and, if
+	// there are none, the mainnet limits.
+	Limits map[uint64]Limits `json:"limits,omitempty"`
+
+	// Irregular are the state changes of the chain that are not
+	// transactions
+	Irregular []*IrregularChange `json:"irregular,omitempty"`
+
+	// BurntContract is the account that receives the base fee of the
+	// transactions instead of burning it (bor) by the block it is set
+	// from. The latest address not after the block is used.
+	BurntContract map[uint64]types.Address `json:"burntContract,omitempty"`
+
+	// BlobSchedule are the blob params of the forks. If nil the params
+	// of the enabled eips are used.
+	BlobSchedule *BlobSchedule `json:"blobSchedule,omitempty"`
+}
+
+// At returns the forks and the eip overrides enabled at the given block
+// number and timestamp together with the limits, the irregular changes
+// and the blob params of the block
+func (p *Params) At(block, timestamp uint64) ForksInTime {
+	forks := p.Forks.At(block, timestamp)
+	if len(p.EIPs) != 0 || len(p.EIPsTime) != 0 {
+		forks.EIPs = make(map[EIP]bool, len(p.EIPs)+len(p.EIPsTime))
+		for eip, fork := range p.EIPs {
+			forks.EIPs[eip] = fork != nil && fork.Active(block)
+		}
+		for eip, fork := range p.EIPsTime {
+			forks.EIPs[eip] = fork != nil && fork.Active(timestamp)
+		}
+	}
+	forks.Limits = p.limitsAt(block)
+	forks.Irregular = p.IrregularAt(block)
+	forks.BurntContract = p.burntContractAt(block)
+	if p.BlobSchedule != nil {
+		forks.Blobs = p.BlobSchedule.At(&forks)
+	}
+	return forks
+}
+
+// burntContractAt returns the burnt contract of the given block number or
+// nil if the base fee is burned
+func (p *Params) burntContractAt(block uint64) *types.Address {
+	var addr *types.Address
+	var from uint64
+	for num, contract := range p.BurntContract {
+		if num <= block && (addr == nil || num > from) {
+			contract := contract
+			addr, from = &contract, num
+		}
+	}
+	return addr
 }
 
-// Forks specifies when each fork is activated
+// limitsAt returns the protocol limits of the given block number or nil
+// if the mainnet limits are used
+func (p *Params) limitsAt(block uint64) *Limits {
+	var limits *Limits
+	var from uint64
+	for num, l := range p.Limits {
+		if num <= block && (limits == nil || num > from) {
+			l := l
+			limits, from = &l, num
+		}
+	}
+	return limits
+}
+
+// Validate checks that the forks are activated in order, that the eips are
+// not overridden both by block and by timestamp and that the blob schedule
+// has the params of the blob parameter only forks
+func (p *Params) Validate() error {
+	if err := p.Forks.Validate(); err != nil {
+		return err
+	}
+	for eip := range p.EIPsTime {
+		if _, ok := p.EIPs[eip]; ok {
+			return fmt.Errorf("eip %d overridden both by block and by timestamp", eip)
+		}
+	}
+	return p.BlobSchedule.validate(p.Forks)
+}
+
+// Forks specifies when each fork is activated. The forks after the
+// merge can be scheduled either by block number or by timestamp.
 type Forks struct {
 	Homestead      *Fork `json:"homestead,omitempty"`
 	Byzantium      *Fork `json:"byzantium,omitempty"`
 	Constantinople *Fork `json:"constantinople,omitempty"`
 	Petersburg     *Fork `json:"petersburg,omitempty"`
 	Istanbul       *Fork `json:"istanbul,omitempty"`
+	Berlin         *Fork `json:"berlin,omitempty"`
+	London         *Fork `json:"london,omitempty"`
+	Paris          *Fork `json:"paris,omitempty"`
+	Shanghai       *Fork `json:"shanghai,omitempty"`
+	Cancun         *Fork `json:"cancun,omitempty"`
+	Prague         *Fork `json:"prague,omitempty"`
+	Osaka          *Fork `json:"osaka,omitempty"`
 	EIP150         *Fork `json:"EIP150,omitempty"`
 	EIP158         *Fork `json:"EIP158,omitempty"`
 	EIP155         *Fork `json:"EIP155,omitempty"`
+
+	ShanghaiTime *Fork `json:"shanghaiTime,omitempty"`
+	CancunTime   *Fork `json:"cancunTime,omitempty"`
+	PragueTime   *Fork `json:"pragueTime,omitempty"`
+	OsakaTime    *Fork `json:"osakaTime,omitempty"`
+
+	// the blob parameter only forks (eip-7892) only change the blob
+	// params of the blob schedule
+	BPO1Time *Fork `json:"bpo1Time,omitempty"`
+	BPO2Time *Fork `json:"bpo2Time,omitempty"`
+	BPO3Time *Fork `json:"bpo3Time,omitempty"`
+	BPO4Time *Fork `json:"bpo4Time,omitempty"`
+	BPO5Time *Fork `json:"bpo5Time,omitempty"`
 }
 
 func (f *Forks) active(ff *Fork, block uint64) bool {
@@ -29,6 +149,11 @@ func (f *Forks) active(ff *Fork, block uint64) bool {
 	return ff.Active(block)
 }
 
+// activeAt returns whether the fork is active either by block or by timestamp
+func (f *Forks) activeAt(ff, fft *Fork, block, timestamp uint64) bool {
+	return f.active(ff, block) || f.active(fft, timestamp)
+}
+
 func (f *Forks) IsHomestead(block uint64) bool {
 	return f.active(f.Homestead, block)
 }
@@ -45,6 +170,34 @@ func (f *Forks) IsPetersburg(block uint64) bool {
 	return f.active(f.Petersburg, block)
 }
 
+func (f *Forks) IsBerlin(block uint64) bool {
+	return f.active(f.Berlin, block)
+}
+
+func (f *Forks) IsLondon(block uint64) bool {
+	return f.active(f.London, block)
+}
+
+func (f *Forks) IsParis(block uint64) bool {
+	return f.active(f.Paris, block)
+}
+
+func (f *Forks) IsShanghai(block, timestamp uint64) bool {
+	return f.activeAt(f.Shanghai, f.ShanghaiTime, block, timestamp)
+}
+
+func (f *Forks) IsCancun(block, timestamp uint64) bool {
+	return f.activeAt(f.Cancun, f.CancunTime, block, timestamp)
+}
+
+func (f *Forks) IsPrague(block, timestamp uint64) bool {
+	return f.activeAt(f.Prague, f.PragueTime, block, timestamp)
+}
+
+func (f *Forks) IsOsaka(block, timestamp uint64) bool {
+	return f.activeAt(f.Osaka, f.OsakaTime, block, timestamp)
+}
+
 func (f *Forks) IsEIP150(block uint64) bool {
 	return f.active(f.EIP150, block)
 }
@@ -57,17 +210,96 @@ func (f *Forks) IsEIP155(block uint64) bool {
 	return f.active(f.EIP155, block)
 }
 
-func (f *Forks) At(block uint64) ForksInTime {
+// At returns the forks enabled at the given block number and timestamp
+func (f *Forks) At(block, timestamp uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
 		Byzantium:      f.active(f.Byzantium, block),
 		Constantinople: f.active(f.Constantinople, block),
 		Petersburg:     f.active(f.Petersburg, block),
 		Istanbul:       f.active(f.Istanbul, block),
+		Berlin:         f.active(f.Berlin, block),
+		London:         f.active(f.London, block),
+		Paris:          f.active(f.Paris, block),
+		Shanghai:       f.activeAt(f.Shanghai, f.ShanghaiTime, block, timestamp),
+		Cancun:         f.activeAt(f.Cancun, f.CancunTime, block, timestamp),
+		Prague:         f.activeAt(f.Prague, f.PragueTime, block, timestamp),
+		Osaka:          f.activeAt(f.Osaka, f.OsakaTime, block, timestamp),
 		EIP150:         f.active(f.EIP150, block),
 		EIP158:         f.active(f.EIP158, block),
 		EIP155:         f.active(f.EIP155, block),
+		BPO1:           f.active(f.BPO1Time, timestamp),
+		BPO2:           f.active(f.BPO2Time, timestamp),
+		BPO3:           f.active(f.BPO3Time, timestamp),
+		BPO4:           f.active(f.BPO4Time, timestamp),
+		BPO5:           f.active(f.BPO5Time, timestamp),
+	}
+}
+
+// Validate checks that the forks are activated in order. A fork cannot be
+// enabled if the previous one is not, cannot be scheduled both by block and
+// by timestamp and cannot be scheduled by block after a fork by timestamp.
+// Paris is optional since the chains without proof of stake never enable it.
+func (f *Forks) Validate() error {
+	type fork struct {
+		name        string
+		block, time *Fork
+		optional    bool
 	}
+
+	forks := []fork{
+		{"homestead", f.Homestead, nil, false},
+		{"EIP150", f.EIP150, nil, false},
+		{"EIP155", f.EIP155, nil, false},
+		{"EIP158", f.EIP158, nil, false},
+		{"byzantium", f.Byzantium, nil, false},
+		{"constantinople", f.Constantinople, nil, false},
+		{"petersburg", f.Petersburg, nil, false},
+		{"istanbul", f.Istanbul, nil, false},
+		{"berlin", f.Berlin, nil, false},
+		{"london", f.London, nil, false},
+		{"paris", f.Paris, nil, true},
+		{"shanghai", f.Shanghai, f.ShanghaiTime, false},
+		{"cancun", f.Cancun, f.CancunTime, false},
+		{"prague", f.Prague, f.PragueTime, false},
+		{"osaka", f.Osaka, f.OsakaTime, false},
+		{"bpo1", nil, f.BPO1Time, false},
+		{"bpo2", nil, f.BPO2Time, false},
+		{"bpo3", nil, f.BPO3Time, false},
+		{"bpo4", nil, f.BPO4Time, false},
+		{"bpo5", nil, f.BPO5Time, false},
+	}
+
+	var last fork
+	for i, cur := range forks {
+		if cur.block != nil && cur.time != nil {
+			return fmt.Errorf("fork %s scheduled both by block and by timestamp", cur.name)
+		}
+		if i == 0 {
+			last = cur
+			continue
+		}
+
+		enabled := cur.block != nil || cur.time != nil
+		lastEnabled := last.block != nil || last.time != nil
+
+		switch {
+		case !enabled && cur.optional:
+			// skip the fork and check the next one against the previous
+			continue
+		case !enabled:
+		case !lastEnabled:
+			return fmt.Errorf("fork %s enabled but %s is not", cur.name, last.name)
+		case last.time != nil && cur.block != nil:
+			return fmt.Errorf("fork %s scheduled by block after %s scheduled by timestamp", cur.name, last.name)
+		case last.block != nil && cur.block != nil && *cur.block < *last.block:
+			return fmt.Errorf("fork %s at block %d before %s at block %d", cur.name, *cur.block, last.name, *last.block)
+		case last.time != nil && cur.time != nil && *cur.time < *last.time:
+			return fmt.Errorf("fork %s at timestamp %d before %s at timestamp %d", cur.name, *cur.time, last.name, *last.time)
+		}
+		last = cur
+	}
+	return nil
 }
 
 type Fork uint64
@@ -92,9 +324,47 @@ type ForksInTime struct {
 	Constantinople,
 	Petersburg,
 	Istanbul,
+	Berlin,
+	London,
+	Paris,
+	Shanghai,
+	Cancun,
+	Prague,
+	Osaka,
 	EIP150,
 	EIP158,
-	EIP155 bool
+	EIP155,
+	BPO1,
+	BPO2,
+	BPO3,
+	BPO4,
+	BPO5 bool
+
+	// EIPs are the eips enabled or disabled independently of the forks
+	EIPs map[EIP]bool
+
+	// Limits are the protocol limits of the block. If nil the mainnet
+	// limits are used.
+	Limits *Limits
+
+	// Irregular are the irregular changes of the block
+	Irregular []*IrregularChange
+
+	// Blobs are the blob params of the block. If nil the params of the
+	// enabled eips are used.
+	Blobs *BlobConfig
+
+	// BurntContract receives the base fee of the transactions. If nil the
+	// base fee is burned.
+	BurntContract *types.Address
+}
+
+// GetLimits returns the protocol limits of the block
+func (f *ForksInTime) GetLimits() Limits {
+	if f.Limits == nil {
+		return MainnetLimits
+	}
+	return *f.Limits
 }
 
 var AllForksEnabled = &Forks{
@@ -106,4 +376,11 @@ var AllForksEnabled = &Forks{
 	Constantinople: NewFork(0),
 	Petersburg:     NewFork(0),
 	Istanbul:       NewFork(0),
+	Berlin:         NewFork(0),
+	London:         NewFork(0),
+	Paris:          NewFork(0),
+	Shanghai:       NewFork(0),
+	Cancun:         NewFork(0),
+	Prague:         NewFork(0),
+	Osaka:          NewFork(0),
 }
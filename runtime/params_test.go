@@ -0,0 +1,164 @@
+package runtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForksAtTimestamp(t *testing.T) {
+	var f Forks
+	data := `{"homestead": 0, "london": 0, "paris": 10, "shanghaiTime": 100, "cancunTime": 200}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &f))
+
+	assert.Equal(t, NewFork(100), f.ShanghaiTime)
+	assert.Nil(t, f.Shanghai)
+
+	forks := f.At(20, 99)
+	assert.True(t, forks.Paris)
+	assert.False(t, forks.Shanghai)
+
+	forks = f.At(20, 100)
+	assert.True(t, forks.Shanghai)
+	assert.False(t, forks.Cancun)
+	assert.True(t, f.IsShanghai(20, 100))
+	assert.False(t, f.IsCancun(20, 100))
+
+	// the forks by block are still supported
+	f.Prague = NewFork(30)
+	assert.True(t, f.At(30, 0).Prague)
+
+	out, err := json.Marshal(&f)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"cancunTime":200`)
+}
+
+func TestForksValidate(t *testing.T) {
+	cases := []struct {
+		forks *Forks
+		valid bool
+	}{
+		{
+			AllForksEnabled,
+			true,
+		},
+		{
+			&Forks{Homestead: NewFork(0), EIP150: NewFork(10), EIP155: NewFork(20)},
+			true,
+		},
+		{
+			// out of order
+			&Forks{Homestead: NewFork(10), EIP150: NewFork(5)},
+			false,
+		},
+		{
+			// gap in the forks
+			&Forks{Homestead: NewFork(0), EIP155: NewFork(5)},
+			false,
+		},
+		{
+			// block forks followed by time forks
+			&Forks{Homestead: NewFork(0), EIP150: NewFork(0), EIP155: NewFork(0), EIP158: NewFork(0), Byzantium: NewFork(0), Constantinople: NewFork(0), Petersburg: NewFork(0), Istanbul: NewFork(0), Berlin: NewFork(0), London: NewFork(0), Paris: NewFork(50), ShanghaiTime: NewFork(1000), CancunTime: NewFork(2000)},
+			true,
+		},
+		{
+			// time forks out of order
+			&Forks{Homestead: NewFork(0), EIP150: NewFork(0), EIP155: NewFork(0), EIP158: NewFork(0), Byzantium: NewFork(0), Constantinople: NewFork(0), Petersburg: NewFork(0), Istanbul: NewFork(0), Berlin: NewFork(0), London: NewFork(0), Paris: NewFork(0), ShanghaiTime: NewFork(2000), CancunTime: NewFork(1000)},
+			false,
+		},
+		{
+			// block fork after a time fork
+			&Forks{Homestead: NewFork(0), EIP150: NewFork(0), EIP155: NewFork(0), EIP158: NewFork(0), Byzantium: NewFork(0), Constantinople: NewFork(0), Petersburg: NewFork(0), Istanbul: NewFork(0), Berlin: NewFork(0), London: NewFork(0), Paris: NewFork(0), ShanghaiTime: NewFork(1000), Cancun: NewFork(2000)},
+			false,
+		},
+		{
+			// paris is optional
+			&Forks{Homestead: NewFork(0), EIP150: NewFork(0), EIP155: NewFork(0), EIP158: NewFork(0), Byzantium: NewFork(0), Constantinople: NewFork(0), Petersburg: NewFork(0), Istanbul: NewFork(0), Berlin: NewFork(0), London: NewFork(0), Shanghai: NewFork(10)},
+			true,
+		},
+		{
+			// fork both by block and by time
+			&Forks{Homestead: NewFork(0), ShanghaiTime: NewFork(0), Shanghai: NewFork(0)},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		err := c.forks.Validate()
+		if c.valid {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+}
+
+func TestParamsLimits(t *testing.T) {
+	var p Params
+	data := `{"forks": {"homestead": 0}, "limits": {"0": {"maxCodeSize": 49152, "codeDepositGas": 0}, "100": {"maxCodeSize": 32768}}}`
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	// the limits that are not set keep the mainnet value
+	forks := p.At(0, 0)
+	limits := forks.GetLimits()
+	assert.Equal(t, 49152, limits.MaxCodeSize)
+	assert.Equal(t, uint64(0), limits.CodeDepositGas)
+	assert.Equal(t, MainnetLimits.MaxInitCodeSize, limits.MaxInitCodeSize)
+	assert.Equal(t, MainnetLimits.MaxCallDepth, limits.MaxCallDepth)
+
+	// the latest limits not after the block are used
+	forks = p.At(99, 0)
+	assert.Equal(t, 49152, forks.GetLimits().MaxCodeSize)
+
+	forks = p.At(100, 0)
+	limits = forks.GetLimits()
+	assert.Equal(t, 32768, limits.MaxCodeSize)
+	assert.Equal(t, MainnetLimits.CodeDepositGas, limits.CodeDepositGas)
+
+	// without limits at the block the mainnet limits are used
+	delete(p.Limits, 0)
+	forks = p.At(99, 0)
+	assert.Equal(t, MainnetLimits, forks.GetLimits())
+
+	p.Limits = nil
+	forks = p.At(100, 0)
+	assert.Equal(t, MainnetLimits, forks.GetLimits())
+}
+
+func TestParamsBlobSchedule(t *testing.T) {
+	// the blob params of the latest enabled fork in the schedule are used
+	p := MainnetParams
+
+	forks := p.At(20000000, 1710338135)
+	assert.Equal(t, CancunBlobConfig, forks.GetBlobConfig())
+
+	// osaka is not in the schedule and keeps the params of prague
+	forks = p.At(20000000, 1764798551)
+	assert.True(t, forks.Osaka)
+	assert.Equal(t, PragueBlobConfig, forks.GetBlobConfig())
+
+	forks = p.At(20000000, 1765290071)
+	assert.True(t, forks.BPO1)
+	assert.Equal(t, uint64(15), forks.GetBlobConfig().Max)
+
+	forks = p.At(20000000, 1767747671)
+	assert.True(t, forks.BPO2)
+	assert.Equal(t, uint64(11684671), forks.GetBlobConfig().UpdateFraction)
+
+	// holesky has the same blob parameter only forks
+	forks = HoleskyParams.At(0, 1760389824)
+	assert.True(t, forks.BPO2)
+	assert.Equal(t, uint64(21), forks.GetBlobConfig().Max)
+
+	// a blob parameter only fork needs blob params
+	forks2 := *AllForksEnabled
+	forks2.BPO1Time = NewFork(0)
+	assert.Error(t, (&Params{Forks: &forks2}).Validate())
+	assert.NoError(t, (&Params{Forks: &forks2, BlobSchedule: &BlobSchedule{BPO1: &PragueBlobConfig}}).Validate())
+
+	// a blob parameter only fork cannot skip the previous one
+	forks2.BPO1Time = nil
+	forks2.BPO2Time = NewFork(0)
+	assert.Error(t, (&Params{Forks: &forks2, BlobSchedule: &BlobSchedule{BPO2: &PragueBlobConfig}}).Validate())
+}
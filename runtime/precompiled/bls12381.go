@@ -0,0 +1,358 @@
+package precompiled
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/0xPolygon/eth-state-transition/helper"
+	"github.com/0xPolygon/eth-state-transition/runtime"
+	"github.com/0xPolygon/eth-state-transition/runtime/precompiled/internal/bls12381"
+)
+
+// eip-2537 encodes every field element in 64 bytes with the top 16 bytes set to zero
+const (
+	blsFpSize      = 64
+	blsG1PointSize = 2 * blsFpSize
+	blsG2PointSize = 4 * blsFpSize
+	blsScalarSize  = 32
+
+	blsG1MSMPairSize   = blsG1PointSize + blsScalarSize
+	blsG2MSMPairSize   = blsG2PointSize + blsScalarSize
+	blsPairingPairSize = blsG1PointSize + blsG2PointSize
+)
+
+var (
+	errBLSInvalidInputLength   = fmt.Errorf("invalid input length")
+	errBLSInvalidFieldPadding  = fmt.Errorf("invalid field element top bytes")
+	errBLSInvalidFieldElement  = fmt.Errorf("invalid fp.Element encoding")
+	errBLSPointNotOnCurve      = fmt.Errorf("invalid point: not on curve")
+	errBLSG1PointNotInSubgroup = fmt.Errorf("g1 point is not on correct subgroup")
+	errBLSG2PointNotInSubgroup = fmt.Errorf("g2 point is not on correct subgroup")
+)
+
+// blsModulus is the modulus of the base field of bls12-381 in 48 bytes
+var blsModulus = helper.MustDecodeHex("0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+
+var (
+	blsG1MSMDiscountTable = [128]uint64{1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677, 673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627, 625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598, 596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576, 575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544, 543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531, 530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519}
+	blsG2MSMDiscountTable = [128]uint64{1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717, 711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646, 643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607, 606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582, 580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547, 546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535, 534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524}
+)
+
+// msmGas returns the gas of a multi scalar multiplication with k pairs
+func msmGas(k int, mulGas uint64, table *[128]uint64) uint64 {
+	if k == 0 {
+		return 0
+	}
+	discount := table[len(table)-1]
+	if k <= len(table) {
+		discount = table[k-1]
+	}
+	return uint64(k) * mulGas * discount / 1000
+}
+
+type blsG1Add struct{}
+
+func (b *blsG1Add) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return 375
+}
+
+func (b *blsG1Add) run(input []byte) ([]byte, error) {
+	if len(input) != 2*blsG1PointSize {
+		return nil, errBLSInvalidInputLength
+	}
+
+	g := bls12381.NewG1()
+
+	// eip-2537: no subgroup check for the addition
+	p0, err := decodeBLSG1Point(g, input[:blsG1PointSize])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeBLSG1Point(g, input[blsG1PointSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.New()
+	g.Add(r, p0, p1)
+	return encodeBLSG1Point(g, r), nil
+}
+
+type blsG1MSM struct{}
+
+func (b *blsG1MSM) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return msmGas(len(input)/blsG1MSMPairSize, 12000, &blsG1MSMDiscountTable)
+}
+
+func (b *blsG1MSM) run(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%blsG1MSMPairSize != 0 {
+		return nil, errBLSInvalidInputLength
+	}
+
+	g := bls12381.NewG1()
+
+	k := len(input) / blsG1MSMPairSize
+	points := make([]*bls12381.PointG1, k)
+	scalars := make([]*big.Int, k)
+
+	for i := 0; i < k; i++ {
+		buf := input[i*blsG1MSMPairSize:]
+
+		p, err := decodeBLSG1Point(g, buf[:blsG1PointSize])
+		if err != nil {
+			return nil, err
+		}
+		if !g.InCorrectSubgroup(p) {
+			return nil, errBLSG1PointNotInSubgroup
+		}
+		points[i] = p
+		scalars[i] = new(big.Int).SetBytes(buf[blsG1PointSize:blsG1MSMPairSize])
+	}
+
+	r := g.New()
+	if _, err := g.MultiExpBig(r, points, scalars); err != nil {
+		return nil, err
+	}
+	return encodeBLSG1Point(g, r), nil
+}
+
+type blsG2Add struct{}
+
+func (b *blsG2Add) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return 600
+}
+
+func (b *blsG2Add) run(input []byte) ([]byte, error) {
+	if len(input) != 2*blsG2PointSize {
+		return nil, errBLSInvalidInputLength
+	}
+
+	g := bls12381.NewG2()
+
+	// eip-2537: no subgroup check for the addition
+	p0, err := decodeBLSG2Point(g, input[:blsG2PointSize])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeBLSG2Point(g, input[blsG2PointSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.New()
+	g.Add(r, p0, p1)
+	return encodeBLSG2Point(g, r), nil
+}
+
+type blsG2MSM struct{}
+
+func (b *blsG2MSM) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return msmGas(len(input)/blsG2MSMPairSize, 22500, &blsG2MSMDiscountTable)
+}
+
+func (b *blsG2MSM) run(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%blsG2MSMPairSize != 0 {
+		return nil, errBLSInvalidInputLength
+	}
+
+	g := bls12381.NewG2()
+
+	k := len(input) / blsG2MSMPairSize
+	points := make([]*bls12381.PointG2, k)
+	scalars := make([]*big.Int, k)
+
+	for i := 0; i < k; i++ {
+		buf := input[i*blsG2MSMPairSize:]
+
+		p, err := decodeBLSG2Point(g, buf[:blsG2PointSize])
+		if err != nil {
+			return nil, err
+		}
+		if !g.InCorrectSubgroup(p) {
+			return nil, errBLSG2PointNotInSubgroup
+		}
+		points[i] = p
+		scalars[i] = new(big.Int).SetBytes(buf[blsG2PointSize:blsG2MSMPairSize])
+	}
+
+	r := g.New()
+	if _, err := g.MultiExpBig(r, points, scalars); err != nil {
+		return nil, err
+	}
+	return encodeBLSG2Point(g, r), nil
+}
+
+type blsPairing struct{}
+
+func (b *blsPairing) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return 37700 + 32600*uint64(len(input)/blsPairingPairSize)
+}
+
+func (b *blsPairing) run(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%blsPairingPairSize != 0 {
+		return nil, errBLSInvalidInputLength
+	}
+
+	e := bls12381.NewEngine()
+
+	k := len(input) / blsPairingPairSize
+	for i := 0; i < k; i++ {
+		buf := input[i*blsPairingPairSize:]
+
+		p1, err := decodeBLSG1Point(e.G1, buf[:blsG1PointSize])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := decodeBLSG2Point(e.G2, buf[blsG1PointSize:blsPairingPairSize])
+		if err != nil {
+			return nil, err
+		}
+		if !e.G1.InCorrectSubgroup(p1) {
+			return nil, errBLSG1PointNotInSubgroup
+		}
+		if !e.G2.InCorrectSubgroup(p2) {
+			return nil, errBLSG2PointNotInSubgroup
+		}
+		e.AddPair(p1, p2)
+	}
+
+	if e.Check() {
+		return trueBytes, nil
+	}
+	return falseBytes, nil
+}
+
+type blsMapG1 struct{}
+
+func (b *blsMapG1) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return 5500
+}
+
+func (b *blsMapG1) run(input []byte) ([]byte, error) {
+	if len(input) != blsFpSize {
+		return nil, errBLSInvalidInputLength
+	}
+
+	fe, err := decodeBLSFieldElement(input)
+	if err != nil {
+		return nil, err
+	}
+
+	g := bls12381.NewG1()
+	r, err := g.MapToCurve(fe)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBLSG1Point(g, r), nil
+}
+
+type blsMapG2 struct{}
+
+func (b *blsMapG2) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	return 23800
+}
+
+func (b *blsMapG2) run(input []byte) ([]byte, error) {
+	if len(input) != 2*blsFpSize {
+		return nil, errBLSInvalidInputLength
+	}
+
+	fe, err := decodeBLSFieldElement2(input)
+	if err != nil {
+		return nil, err
+	}
+
+	g := bls12381.NewG2()
+	r, err := g.MapToCurve(fe)
+	if err != nil {
+		return nil, err
+	}
+	return encodeBLSG2Point(g, r), nil
+}
+
+// decodeBLSFieldElement strips the zero padding of a 64 bytes field element
+// and checks that the value is below the modulus
+func decodeBLSFieldElement(in []byte) ([]byte, error) {
+	for _, b := range in[:blsFpSize-48] {
+		if b != 0 {
+			return nil, errBLSInvalidFieldPadding
+		}
+	}
+	fe := in[blsFpSize-48 : blsFpSize]
+	if bytes.Compare(fe, blsModulus) >= 0 {
+		return nil, errBLSInvalidFieldElement
+	}
+	return fe, nil
+}
+
+// decodeBLSFieldElement2 decodes an (c0, c1) extension field element into the
+// (c1, c0) order used by the curve library
+func decodeBLSFieldElement2(in []byte) ([]byte, error) {
+	c0, err := decodeBLSFieldElement(in[:blsFpSize])
+	if err != nil {
+		return nil, err
+	}
+	c1, err := decodeBLSFieldElement(in[blsFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, c1...), c0...), nil
+}
+
+func decodeBLSG1Point(g *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	x, err := decodeBLSFieldElement(in[:blsFpSize])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBLSFieldElement(in[blsFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	// the point at infinity is encoded as (0, 0). The coordinates are
+	// valid field elements, so the library only fails if the point is
+	// not on the curve.
+	p, err := g.FromBytes(append(append([]byte{}, x...), y...))
+	if err != nil {
+		return nil, errBLSPointNotOnCurve
+	}
+	return p, nil
+}
+
+func decodeBLSG2Point(g *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	x, err := decodeBLSFieldElement2(in[:2*blsFpSize])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBLSFieldElement2(in[2*blsFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	// the point at infinity is encoded as (0, 0)
+	p, err := g.FromBytes(append(x, y...))
+	if err != nil {
+		return nil, errBLSPointNotOnCurve
+	}
+	return p, nil
+}
+
+func encodeBLSG1Point(g *bls12381.G1, p *bls12381.PointG1) []byte {
+	buf := g.ToBytes(p)
+
+	out := make([]byte, blsG1PointSize)
+	copy(out[16:blsFpSize], buf[:48])
+	copy(out[blsFpSize+16:], buf[48:])
+	return out
+}
+
+func encodeBLSG2Point(g *bls12381.G2, p *bls12381.PointG2) []byte {
+	buf := g.ToBytes(p)
+
+	// the library writes each coordinate as (c1, c0)
+	out := make([]byte, blsG2PointSize)
+	copy(out[16:blsFpSize], buf[48:96])
+	copy(out[blsFpSize+16:2*blsFpSize], buf[:48])
+	copy(out[2*blsFpSize+16:3*blsFpSize], buf[144:192])
+	copy(out[3*blsFpSize+16:], buf[96:144])
+	return out
+}
@@ -0,0 +1,74 @@
+package precompiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/runtime"
+	"github.com/0xPolygon/eth-state-transition/types"
+)
+
+func TestBLS12381(t *testing.T) {
+	config := &runtime.ForksInTime{Prague: true}
+
+	cases := []struct {
+		fixture  string
+		contract contract
+	}{
+		{"blsG1Add", &blsG1Add{}},
+		{"blsG1Mul", &blsG1MSM{}},
+		{"blsG1MultiExp", &blsG1MSM{}},
+		{"blsG2Add", &blsG2Add{}},
+		{"blsG2Mul", &blsG2MSM{}},
+		{"blsG2MultiExp", &blsG2MSM{}},
+		{"blsPairing", &blsPairing{}},
+		{"blsMapG1", &blsMapG1{}},
+		{"blsMapG2", &blsMapG2{}},
+	}
+
+	for _, cc := range cases {
+		b := cc.contract
+
+		t.Run(cc.fixture, func(t *testing.T) {
+			ReadTestCase(t, cc.fixture+".json", func(t *testing.T, c *TestCase) {
+				if gas := b.gas(c.Input, config); gas != c.Gas {
+					t.Fatalf("bad gas: expected %d but found %d", c.Gas, gas)
+				}
+				out, err := b.run(c.Input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(c.Expected, out) {
+					t.Fatal("bad")
+				}
+			})
+		})
+
+		t.Run("fail-"+cc.fixture, func(t *testing.T) {
+			ReadTestCase(t, "fail-"+cc.fixture+".json", func(t *testing.T, c *TestCase) {
+				_, err := b.run(c.Input)
+				if err == nil {
+					t.Fatalf("expected error: %s", c.ExpectedError)
+				}
+				if err.Error() != c.ExpectedError {
+					t.Fatalf("bad error: expected %s but found %s", c.ExpectedError, err)
+				}
+			})
+		})
+	}
+}
+
+func TestBLS12381Enabled(t *testing.T) {
+	p := NewPrecompiled()
+
+	addrs := []string{"b", "c", "d", "e", "f", "10", "11"}
+	for _, addr := range addrs {
+		c := &runtime.Contract{CodeAddress: types.StringToAddress(addr)}
+		if p.CanRun(c, nil, &runtime.ForksInTime{Istanbul: true}) {
+			t.Fatalf("%s enabled before prague", addr)
+		}
+		if !p.CanRun(c, nil, &runtime.ForksInTime{Prague: true}) {
+			t.Fatalf("%s not enabled in prague", addr)
+		}
+	}
+}
@@ -13,7 +13,7 @@ type bn256Add struct {
 }
 
 func (b *bn256Add) gas(input []byte, config *runtime.ForksInTime) uint64 {
-	if config.Istanbul {
+	if config.Enabled(runtime.EIP1108) {
 		return 150
 	}
 	return 500
@@ -45,7 +45,7 @@ type bn256Mul struct {
 }
 
 func (b *bn256Mul) gas(input []byte, config *runtime.ForksInTime) uint64 {
-	if config.Istanbul {
+	if config.Enabled(runtime.EIP1108) {
 		return 6000
 	}
 	return 40000
@@ -83,7 +83,7 @@ type bn256Pairing struct {
 
 func (b *bn256Pairing) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	baseGas, pointGas := uint64(100000), uint64(80000)
-	if config.Istanbul {
+	if config.Enabled(runtime.EIP1108) {
 		baseGas, pointGas = 45000, 34000
 	}
 	return baseGas + pointGas*uint64(len(input)/192)
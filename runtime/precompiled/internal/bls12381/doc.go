@@ -0,0 +1,5 @@
+// Package bls12381 is a copy of github.com/kilic/bls12-381 v0.1.0.
+//
+// The upstream module is archived, so the code used by the bls12-381
+// precompiles (eip-2537) is kept in the tree and maintained here.
+package bls12381
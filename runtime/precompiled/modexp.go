@@ -28,6 +28,13 @@ var (
 
 var (
 	divisor = big.NewInt(20)
+
+	// divisor and minimum gas after berlin (eip-2565)
+	berlinDivisor = big.NewInt(3)
+	berlinMinGas  = big.NewInt(200)
+
+	// minimum gas after osaka (eip-7883)
+	osakaMinGas = big.NewInt(500)
 )
 
 func adjustedExponentLength(len, head *big.Int) *big.Int {
@@ -76,6 +83,13 @@ func multComplexity(x *big.Int) *big.Int {
 	return x
 }
 
+func berlinMultComplexity(x *big.Int) *big.Int {
+	// ceil(x / 8) ** 2
+	x.Add(x, big.NewInt(7))
+	x.Div(x, big8)
+	return x.Mul(x, x)
+}
+
 func (m *modExp) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	// fmt.Println("-- calc gas --")
 
@@ -102,11 +116,21 @@ func (m *modExp) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	}
 
 	expHead := new(big.Int)
-	if bLen := baseLen.Uint64(); bLen < uint64(len(input)) {
+	if bLen := baseLen.Uint64(); baseLen.IsUint64() && bLen < uint64(len(input)) {
 		val, _ = m.p.get(input[bLen:], int(expHeadLen))
 		expHead.SetBytes(val)
 	}
 
+	if config.Enabled(runtime.EIP7823) {
+		// the call runs out of gas if any length is above 1024 bytes
+		if baseLen.Cmp(big1024) > 0 || expLen.Cmp(big1024) > 0 || modLen.Cmp(big1024) > 0 {
+			return math.MaxUint64
+		}
+	}
+	if config.Enabled(runtime.EIP7883) {
+		return osakaModExpGas(baseLen, expLen, modLen, expHead)
+	}
+
 	// a := mult_complexity(max(length_of_MODULUS, length_of_BASE)
 	gasCost := new(big.Int)
 	if modLen.Cmp(baseLen) >= 0 {
@@ -114,7 +138,11 @@ func (m *modExp) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	} else {
 		gasCost.Set(baseLen)
 	}
-	gasCost = multComplexity(gasCost)
+	if config.Enabled(runtime.EIP2565) {
+		gasCost = berlinMultComplexity(gasCost)
+	} else {
+		gasCost = multComplexity(gasCost)
+	}
 
 	// a = a * max(ADJUSTED_EXPONENT_LENGTH, 1)
 	adjExpLen := adjustedExponentLength(expLen, expHead)
@@ -125,7 +153,14 @@ func (m *modExp) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	}
 
 	// a = a / div
-	gasCost.Div(gasCost, divisor)
+	if config.Enabled(runtime.EIP2565) {
+		gasCost.Div(gasCost, berlinDivisor)
+		if gasCost.Cmp(berlinMinGas) < 0 {
+			gasCost.Set(berlinMinGas)
+		}
+	} else {
+		gasCost.Div(gasCost, divisor)
+	}
 
 	// cap to the max uint64
 	if !gasCost.IsUint64() {
@@ -134,6 +169,48 @@ func (m *modExp) gas(input []byte, config *runtime.ForksInTime) uint64 {
 	return gasCost.Uint64()
 }
 
+// osakaModExpGas returns the gas of the modexp with the repricing (eip-7883)
+// of osaka
+func osakaModExpGas(baseLen, expLen, modLen, expHead *big.Int) uint64 {
+	maxLen := new(big.Int).Set(baseLen)
+	if modLen.Cmp(maxLen) > 0 {
+		maxLen.Set(modLen)
+	}
+
+	// 16 if max_length <= 32 else 2 * words ** 2
+	complexity := big.NewInt(16)
+	if maxLen.Cmp(big32) > 0 {
+		words := maxLen.Add(maxLen, big.NewInt(7))
+		words.Div(words, big8)
+		complexity.Mul(words, words)
+		complexity.Lsh(complexity, 1)
+	}
+
+	// 16 * (length_of_EXPONENT - 32) + the index of the highest bit of the head
+	iterations := new(big.Int)
+	if expLen.Cmp(big32) > 0 {
+		iterations.Sub(expLen, big32)
+		iterations.Mul(iterations, big16)
+	}
+	if expHead.Sign() != 0 {
+		iterations.Add(iterations, big.NewInt(int64(expHead.BitLen()-1)))
+	}
+	if iterations.Sign() == 0 {
+		iterations.Set(big1)
+	}
+
+	gas := complexity.Mul(complexity, iterations)
+	if gas.Cmp(osakaMinGas) < 0 {
+		gas.Set(osakaMinGas)
+	}
+
+	// cap to the max uint64
+	if !gas.IsUint64() {
+		return math.MaxUint64
+	}
+	return gas.Uint64()
+}
+
 func (m *modExp) run(input []byte) ([]byte, error) {
 	// get the lengths
 	var baseLen, exponentLen, modulusLen uint64
@@ -1,7 +1,14 @@
 package precompiled
 
 import (
+	"bytes"
+	"math"
+	"math/big"
 	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/helper"
+	"github.com/0xPolygon/eth-state-transition/runtime"
+	"github.com/stretchr/testify/assert"
 )
 
 var modExpTests = []precompiledTest{
@@ -90,3 +97,108 @@ func TestModExp(t *testing.T) {
 	p := &Precompiled{}
 	testPrecompiled(t, &modExp{p}, modExpTests)
 }
+
+// gas of the modExpTests before (eip-198) and after (eip-2565) berlin
+var modExpGasTests = []struct {
+	Name      string
+	Byzantium uint64
+	Berlin    uint64
+}{
+	{"eip_example2", 13056, 1360},
+	{"nagydani-1-square", 204, 200},
+	{"nagydani-1-qube", 204, 200},
+	{"nagydani-1-pow0x10001", 3276, 341},
+	{"nagydani-2-square", 665, 200},
+	{"nagydani-2-qube", 665, 200},
+	{"nagydani-2-pow0x10001", 10649, 1365},
+	{"nagydani-3-square", 1894, 341},
+	{"nagydani-3-qube", 1894, 341},
+	{"nagydani-3-pow0x10001", 30310, 5461},
+	{"nagydani-4-square", 5580, 1365},
+	{"nagydani-4-qube", 5580, 1365},
+	{"nagydani-4-pow0x10001", 89292, 21845},
+	{"nagydani-5-square", 17868, 5461},
+	{"nagydani-5-qube", 17868, 5461},
+	{"nagydani-5-pow0x10001", 285900, 87381},
+}
+
+func TestModExpGas(t *testing.T) {
+	p := &Precompiled{}
+	m := &modExp{p}
+
+	inputs := map[string]string{}
+	for _, c := range modExpTests {
+		inputs[c.Name] = c.Input
+	}
+
+	byzantium := &runtime.ForksInTime{Byzantium: true}
+	berlin := &runtime.ForksInTime{Byzantium: true, Berlin: true}
+
+	for _, c := range modExpGasTests {
+		t.Run(c.Name, func(t *testing.T) {
+			input, ok := inputs[c.Name]
+			if !ok {
+				t.Fatal("input not found")
+			}
+			h, _ := helper.DecodeString(input)
+
+			assert.Equal(t, c.Byzantium, m.gas(h, byzantium))
+			assert.Equal(t, c.Berlin, m.gas(h, berlin))
+		})
+	}
+}
+
+func TestModExpOsaka(t *testing.T) {
+	p := &Precompiled{}
+	m := &modExp{p}
+	config := &runtime.ForksInTime{Byzantium: true, Berlin: true, Osaka: true}
+
+	ReadTestCase(t, "modexp_eip7883.json", func(t *testing.T, c *TestCase) {
+		if gas := m.gas(c.Input, config); gas != c.Gas {
+			t.Fatalf("bad gas: expected %d but found %d", c.Gas, gas)
+		}
+		out, err := m.run(c.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(c.Expected, out) {
+			t.Fatal("bad")
+		}
+	})
+
+	// the lengths are bounded to 1024 bytes (eip-7823)
+	input := make([]byte, 96)
+	input[30] = 0x04
+	input[31] = 0x01
+	if gas := m.gas(input, config); gas != math.MaxUint64 {
+		t.Fatalf("expected the max gas but found %d", gas)
+	}
+
+	// without the bounds the gas is capped and does not overflow
+	unbounded := &runtime.ForksInTime{Byzantium: true, Berlin: true, Osaka: true, EIPs: map[runtime.EIP]bool{runtime.EIP7823: false}}
+
+	cases := [][3]uint64{
+		// the complexity overflows an uint64
+		{1 << 40, 0, 0},
+		// the iterations overflow an uint64
+		{0, 1 << 62, 0},
+		// the complexity times the iterations overflows an uint64
+		{1 << 20, 1 << 30, 0},
+	}
+	for _, c := range cases {
+		input := make([]byte, 96)
+		for i, l := range c {
+			new(big.Int).SetUint64(l).FillBytes(input[i*32 : (i+1)*32])
+		}
+		if gas := m.gas(input, unbounded); gas != math.MaxUint64 {
+			t.Fatalf("expected the max gas for %v but found %d", c, gas)
+		}
+	}
+
+	// lengths above an uint64
+	input = make([]byte, 96)
+	input[0] = 0x01
+	if gas := m.gas(input, unbounded); gas != math.MaxUint64 {
+		t.Fatalf("expected the max gas but found %d", gas)
+	}
+}
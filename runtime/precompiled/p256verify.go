@@ -0,0 +1,44 @@
+package precompiled
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/0xPolygon/eth-state-transition/runtime"
+)
+
+// p256Verify verifies a secp256r1 signature (eip-7951, rip-7212)
+type p256Verify struct{}
+
+func (p *p256Verify) gas(input []byte, config *runtime.ForksInTime) uint64 {
+	if !config.Enabled(runtime.EIP7951) {
+		// rip-7212
+		return 3450
+	}
+	return 6900
+}
+
+// run returns an empty output if the signature is not valid. The input is the
+// message hash, the r and s values of the signature and the public key (x, y).
+func (p *p256Verify) run(input []byte) ([]byte, error) {
+	if len(input) != 160 {
+		return nil, nil
+	}
+
+	hash := input[0:32]
+	r, s := new(big.Int).SetBytes(input[32:64]), new(big.Int).SetBytes(input[64:96])
+	x, y := new(big.Int).SetBytes(input[96:128]), new(big.Int).SetBytes(input[128:160])
+
+	// the public key is a point in the curve different from infinity
+	curve := elliptic.P256()
+	if !curve.IsOnCurve(x, y) {
+		return nil, nil
+	}
+
+	pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	if !ecdsa.Verify(pub, hash, r, s) {
+		return nil, nil
+	}
+	return trueBytes, nil
+}
@@ -0,0 +1,48 @@
+package precompiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xPolygon/eth-state-transition/runtime"
+	"github.com/0xPolygon/eth-state-transition/types"
+)
+
+func TestP256Verify(t *testing.T) {
+	p := &p256Verify{}
+	config := &runtime.ForksInTime{Osaka: true}
+
+	ReadTestCase(t, "p256Verify.json", func(t *testing.T, c *TestCase) {
+		if gas := p.gas(c.Input, config); gas != c.Gas {
+			t.Fatalf("bad gas: expected %d but found %d", c.Gas, gas)
+		}
+		out, err := p.run(c.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(c.Expected, out) {
+			t.Fatal("bad")
+		}
+	})
+}
+
+func TestP256VerifyEnabled(t *testing.T) {
+	p := NewPrecompiled()
+
+	c := &runtime.Contract{CodeAddress: types.StringToAddress("100")}
+	if p.CanRun(c, nil, &runtime.ForksInTime{Prague: true}) {
+		t.Fatal("enabled before osaka")
+	}
+	if !p.CanRun(c, nil, &runtime.ForksInTime{Prague: true, Osaka: true}) {
+		t.Fatal("not enabled in osaka")
+	}
+
+	// bor enables it before osaka with half the gas (rip-7212)
+	config := &runtime.ForksInTime{Prague: true, EIPs: map[runtime.EIP]bool{runtime.RIP7212: true}}
+	if !p.CanRun(c, nil, config) {
+		t.Fatal("not enabled with rip-7212")
+	}
+	if gas := (&p256Verify{}).gas(nil, config); gas != 3450 {
+		t.Fatalf("bad gas: expected 3450 but found %d", gas)
+	}
+}
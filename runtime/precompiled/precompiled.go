@@ -41,6 +41,18 @@ func (p *Precompiled) setupContracts() {
 
 	// Istanbul fork
 	p.register("9", &blake2f{p})
+
+	// Prague fork
+	p.register("b", &blsG1Add{})
+	p.register("c", &blsG1MSM{})
+	p.register("d", &blsG2Add{})
+	p.register("e", &blsG2MSM{})
+	p.register("f", &blsPairing{})
+	p.register("10", &blsMapG1{})
+	p.register("11", &blsMapG2{})
+
+	// Osaka fork
+	p.register("100", &p256Verify{})
 }
 
 func (p *Precompiled) register(addrStr string, b contract) {
@@ -56,30 +68,52 @@ var (
 	seven = types.StringToAddress("7")
 	eight = types.StringToAddress("8")
 	nine  = types.StringToAddress("9")
+
+	blsG1AddAddr   = types.StringToAddress("b")
+	blsG1MSMAddr   = types.StringToAddress("c")
+	blsG2AddAddr   = types.StringToAddress("d")
+	blsG2MSMAddr   = types.StringToAddress("e")
+	blsPairingAddr = types.StringToAddress("f")
+	blsMapG1Addr   = types.StringToAddress("10")
+	blsMapG2Addr   = types.StringToAddress("11")
+
+	p256VerifyAddr = types.StringToAddress("100")
 )
 
 // CanRun implements the runtime interface
 func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *runtime.ForksInTime) bool {
-	if _, ok := p.contracts[c.CodeAddress]; !ok {
+	return p.isEnabled(c.CodeAddress, config)
+}
+
+// Addresses returns the addresses of the precompiled contracts enabled in the given fork
+func (p *Precompiled) Addresses(config *runtime.ForksInTime) []types.Address {
+	addrs := []types.Address{}
+	for addr := range p.contracts {
+		if p.isEnabled(addr, config) {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (p *Precompiled) isEnabled(addr types.Address, config *runtime.ForksInTime) bool {
+	if _, ok := p.contracts[addr]; !ok {
 		return false
 	}
 
-	// byzantium precompiles
-	switch c.CodeAddress {
+	switch addr {
 	case five:
-		fallthrough
-	case six:
-		fallthrough
-	case seven:
-		fallthrough
+		return config.Enabled(runtime.EIP198)
+	case six, seven:
+		return config.Enabled(runtime.EIP196)
 	case eight:
-		return config.Byzantium
-	}
-
-	// istanbul precompiles
-	switch c.CodeAddress {
+		return config.Enabled(runtime.EIP197)
 	case nine:
-		return config.Istanbul
+		return config.Enabled(runtime.EIP152)
+	case blsG1AddAddr, blsG1MSMAddr, blsG2AddAddr, blsG2MSMAddr, blsPairingAddr, blsMapG1Addr, blsMapG2Addr:
+		return config.Enabled(runtime.EIP2537)
+	case p256VerifyAddr:
+		return config.Enabled(runtime.EIP7951) || config.Enabled(runtime.RIP7212)
 	}
 
 	return true
@@ -10,10 +10,11 @@ import (
 )
 
 type TestCase struct {
-	Name     string
-	Input    []byte
-	Expected []byte
-	Gas      uint64
+	Name          string
+	Input         []byte
+	Expected      []byte
+	ExpectedError string
+	Gas           uint64
 }
 
 func ReadTestCase(t *testing.T, path string, f func(t *testing.T, c *TestCase)) {
@@ -23,10 +24,11 @@ func ReadTestCase(t *testing.T, path string, f func(t *testing.T, c *TestCase))
 	}
 
 	type testCase struct {
-		Name     string
-		Input    string
-		Expected string
-		Gas      uint64
+		Name          string
+		Input         string
+		Expected      string
+		ExpectedError string
+		Gas           uint64
 	}
 	var cases []*testCase
 	if err := json.Unmarshal(data, &cases); err != nil {
@@ -35,10 +37,11 @@ func ReadTestCase(t *testing.T, path string, f func(t *testing.T, c *TestCase))
 
 	for _, i := range cases {
 		c := &TestCase{
-			Name:     i.Name,
-			Gas:      i.Gas,
-			Input:    helper.MustDecodeHex("0x" + i.Input),
-			Expected: helper.MustDecodeHex("0x" + i.Expected),
+			Name:          i.Name,
+			Gas:           i.Gas,
+			Input:         helper.MustDecodeHex("0x" + i.Input),
+			Expected:      helper.MustDecodeHex("0x" + i.Expected),
+			ExpectedError: i.ExpectedError,
 		}
 		t.Run(i.Name, func(t *testing.T) {
 			f(t, c)
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"bytes"
 	"errors"
 	"math/big"
 
@@ -18,6 +19,21 @@ type TxContext struct {
 	GasLimit   int64
 	ChainID    int64
 	Difficulty types.Hash
+	BaseFee    types.Hash
+
+	// Random is the randomness of the beacon chain (mixHash) after the merge
+	Random types.Hash
+
+	// ExcessBlobGas is the excess blob gas of the block (eip-4844)
+	ExcessBlobGas uint64
+	// BlobBaseFee is the blob base fee of the block. If empty, it is
+	// derived from ExcessBlobGas (eip-4844)
+	BlobBaseFee types.Hash
+	// BlobHashes are the versioned hashes of the transaction (eip-4844)
+	BlobHashes []types.Hash
+
+	// ParentBeaconRoot is the root of the parent beacon block (eip-4788)
+	ParentBeaconRoot types.Hash
 }
 
 // StorageStatus is the status of the storage access
@@ -69,6 +85,12 @@ type Host interface {
 	Callx(*Contract, Host) *ExecutionResult
 	Empty(addr types.Address) bool
 	GetNonce(addr types.Address) uint64
+	AddressInAccessList(addr types.Address) bool
+	SlotInAccessList(addr types.Address, slot types.Hash) (addressOk bool, slotOk bool)
+	AddAddressToAccessList(addr types.Address)
+	AddSlotToAccessList(addr types.Address, slot types.Hash)
+	GetTransientStorage(addr types.Address, key types.Hash) types.Hash
+	SetTransientStorage(addr types.Address, key types.Hash, value types.Hash)
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -104,8 +126,29 @@ var (
 	ErrDepth                    = errors.New("max call depth exceeded")
 	ErrExecutionReverted        = errors.New("execution was reverted")
 	ErrCodeStoreOutOfGas        = errors.New("contract creation code storage out of gas")
+	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
+	ErrMaxInitCodeSizeExceeded  = errors.New("max initcode size exceeded")
 )
 
+// InitCodeWordGas is the gas paid per word of initcode (eip-3860)
+const InitCodeWordGas uint64 = 2
+
+// DelegationPrefix is the prefix of the code of a delegated account (eip-7702)
+var DelegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// ParseDelegation returns the address the code delegates to (eip-7702)
+func ParseDelegation(code []byte) (types.Address, bool) {
+	if len(code) != len(DelegationPrefix)+types.AddressLength || !bytes.HasPrefix(code, DelegationPrefix) {
+		return types.Address{}, false
+	}
+	return types.BytesToAddress(code[len(DelegationPrefix):]), true
+}
+
+// AddressToDelegation returns the code that delegates to the address (eip-7702)
+func AddressToDelegation(addr types.Address) []byte {
+	return append(append([]byte{}, DelegationPrefix...), addr.Bytes()...)
+}
+
 type CallType int
 
 const (
@@ -13,15 +13,6 @@ import (
 	"github.com/0xPolygon/eth-state-transition/types"
 )
 
-var mainnetChainConfig = runtime.Params{
-	Forks: &runtime.Forks{
-		Homestead: runtime.NewFork(1150000),
-		EIP150:    runtime.NewFork(2463000),
-		EIP158:    runtime.NewFork(2675000),
-		Byzantium: runtime.NewFork(4370000),
-	},
-}
-
 var vmTests = "VMTests"
 
 type VMCase struct {
@@ -44,12 +35,12 @@ func testVMCase(t *testing.T, name string, c *VMCase) {
 
 	snap, _ := buildState(t, c.Pre)
 
-	config := mainnetChainConfig.Forks.At(uint64(env.Number))
+	config := runtime.MainnetParams.At(uint64(env.Number), uint64(env.Timestamp))
 
 	runtimeCtx := c.Env.ToHeader(t)
-	runtimeCtx.ChainID = int64(mainnetChainConfig.ChainID)
+	runtimeCtx.ChainID = int64(runtime.MainnetParams.ChainID)
 
-	forks := mainnetChainConfig.Forks.At(uint64(runtimeCtx.Number))
+	forks := runtime.MainnetParams.At(uint64(runtimeCtx.Number), uint64(runtimeCtx.Timestamp))
 	transition := state.NewTransition(forks, runtimeCtx, snap)
 
 	evmR := evm.NewEVM()
@@ -42,7 +42,7 @@ func RunSpecificTest(file string, t *testing.T, c stateCase, name, fork string,
 	}
 
 	snap, _ := buildState(t, c.Pre)
-	forks := config.At(uint64(env.Number))
+	forks := config.At(uint64(env.Number), uint64(env.Timestamp))
 
 	runtimeCtx := c.Env.ToHeader(t)
 	runtimeCtx.ChainID = 1
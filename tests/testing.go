@@ -36,6 +36,10 @@ type env struct {
 	GasLimit   string `json:"currentGasLimit"`
 	Number     string `json:"currentNumber"`
 	Timestamp  string `json:"currentTimestamp"`
+	BaseFee    string `json:"currentBaseFee"`
+	Random     string `json:"currentRandom"`
+
+	ExcessBlobGas string `json:"currentExcessBlobGas"`
 }
 
 func remove0xPrefix(str string) string {
@@ -125,13 +129,23 @@ func (e *env) ToHeader(t *testing.T) runtime.TxContext {
 }
 
 func (e *env) ToEnv(t *testing.T) runtime.TxContext {
-	return runtime.TxContext{
+	ctx := runtime.TxContext{
 		Coinbase:   stringToAddressT(t, e.Coinbase),
 		Difficulty: stringToHashT(t, e.Difficulty),
 		GasLimit:   stringToInt64T(t, e.GasLimit),
 		Number:     stringToInt64T(t, e.Number),
 		Timestamp:  stringToInt64T(t, e.Timestamp),
 	}
+	if e.BaseFee != "" {
+		ctx.BaseFee = stringToHashT(t, e.BaseFee)
+	}
+	if e.Random != "" {
+		ctx.Random = stringToHashT(t, e.Random)
+	}
+	if e.ExcessBlobGas != "" {
+		ctx.ExcessBlobGas = stringToUint64T(t, e.ExcessBlobGas)
+	}
+	return ctx
 }
 
 type exec struct {
@@ -254,13 +268,19 @@ func (p *postEntry) UnmarshalJSON(input []byte) error {
 }
 
 type stTransaction struct {
-	Data     []string       `json:"data"`
-	GasLimit []uint64       `json:"gasLimit"`
-	Value    []*big.Int     `json:"value"`
-	GasPrice *big.Int       `json:"gasPrice"`
-	Nonce    uint64         `json:"nonce"`
-	From     types.Address  `json:"secretKey"`
-	To       *types.Address `json:"to"`
+	Data        []string                     `json:"data"`
+	GasLimit    []uint64                     `json:"gasLimit"`
+	Value       []*big.Int                   `json:"value"`
+	GasPrice    *big.Int                     `json:"gasPrice"`
+	GasFeeCap   *big.Int                     `json:"maxFeePerGas"`
+	GasTipCap   *big.Int                     `json:"maxPriorityFeePerGas"`
+	Nonce       uint64                       `json:"nonce"`
+	From        types.Address                `json:"secretKey"`
+	To          *types.Address               `json:"to"`
+	AccessLists []state.AccessList           `json:"accessLists"`
+	BlobFeeCap  *big.Int                     `json:"maxFeePerBlobGas"`
+	BlobHashes  []types.Hash                 `json:"blobVersionedHashes"`
+	AuthList    []state.SetCodeAuthorization `json:"authorizationList"`
 }
 
 func (t *stTransaction) At(i indexes) (*state.Transaction, error) {
@@ -275,12 +295,37 @@ func (t *stTransaction) At(i indexes) (*state.Transaction, error) {
 	}
 
 	msg := &state.Transaction{
-		To:       t.To,
-		Nonce:    t.Nonce,
-		Value:    new(big.Int).Set(t.Value[i.Value]),
-		Gas:      t.GasLimit[i.Gas],
-		GasPrice: new(big.Int).Set(t.GasPrice),
-		Input:    helper.MustDecodeHex(t.Data[i.Data]),
+		To:    t.To,
+		Nonce: t.Nonce,
+		Value: new(big.Int).Set(t.Value[i.Value]),
+		Gas:   t.GasLimit[i.Gas],
+		Input: helper.MustDecodeHex(t.Data[i.Data]),
+	}
+
+	if t.GasFeeCap != nil {
+		msg.Type = state.DynamicFeeTx
+		msg.GasFeeCap = new(big.Int).Set(t.GasFeeCap)
+		msg.GasTipCap = new(big.Int).Set(t.GasTipCap)
+	} else {
+		msg.GasPrice = new(big.Int).Set(t.GasPrice)
+	}
+
+	if i.Data < len(t.AccessLists) && t.AccessLists[i.Data] != nil {
+		if msg.Type == state.LegacyTx {
+			msg.Type = state.AccessListTx
+		}
+		msg.AccessList = t.AccessLists[i.Data].Copy()
+	}
+
+	if t.BlobFeeCap != nil {
+		msg.Type = state.BlobTx
+		msg.BlobFeeCap = new(big.Int).Set(t.BlobFeeCap)
+		msg.BlobHashes = append([]types.Hash{}, t.BlobHashes...)
+	}
+
+	if t.AuthList != nil {
+		msg.Type = state.SetCodeTx
+		msg.AuthList = append([]state.SetCodeAuthorization{}, t.AuthList...)
 	}
 
 	msg.From = t.From
@@ -288,14 +333,34 @@ func (t *stTransaction) At(i indexes) (*state.Transaction, error) {
 }
 
 func (t *stTransaction) UnmarshalJSON(input []byte) error {
+	type accessTuple struct {
+		Address     string   `json:"address"`
+		StorageKeys []string `json:"storageKeys"`
+	}
+
+	type authorization struct {
+		ChainID string `json:"chainId"`
+		Address string `json:"address"`
+		Nonce   string `json:"nonce"`
+		V       string `json:"v"`
+		R       string `json:"r"`
+		S       string `json:"s"`
+	}
+
 	type txUnmarshall struct {
-		Data      []string `json:"data"`
-		GasLimit  []string `json:"gasLimit"`
-		Value     []string `json:"value"`
-		GasPrice  string   `json:"gasPrice"`
-		Nonce     string   `json:"nonce"`
-		SecretKey string   `json:"secretKey"`
-		To        string   `json:"to"`
+		Data        []string         `json:"data"`
+		GasLimit    []string         `json:"gasLimit"`
+		Value       []string         `json:"value"`
+		GasPrice    string           `json:"gasPrice"`
+		GasFeeCap   string           `json:"maxFeePerGas"`
+		GasTipCap   string           `json:"maxPriorityFeePerGas"`
+		Nonce       string           `json:"nonce"`
+		SecretKey   string           `json:"secretKey"`
+		To          string           `json:"to"`
+		AccessLists []*[]accessTuple `json:"accessLists"`
+		BlobFeeCap  string           `json:"maxFeePerBlobGas"`
+		BlobHashes  []string         `json:"blobVersionedHashes"`
+		AuthList    []*authorization `json:"authorizationList"`
 	}
 
 	var dec txUnmarshall
@@ -331,9 +396,17 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 		t.Value = append(t.Value, value)
 	}
 
-	t.GasPrice, err = stringToBigInt(dec.GasPrice)
-	if err != nil {
-		return err
+	if dec.GasFeeCap != "" {
+		if t.GasFeeCap, err = stringToBigInt(dec.GasFeeCap); err != nil {
+			return err
+		}
+		if t.GasTipCap, err = stringToBigInt(dec.GasTipCap); err != nil {
+			return err
+		}
+	} else {
+		if t.GasPrice, err = stringToBigInt(dec.GasPrice); err != nil {
+			return err
+		}
 	}
 
 	t.Nonce, err = stringToUint64(dec.Nonce)
@@ -358,6 +431,61 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 		address := types.StringToAddress(dec.To)
 		t.To = &address
 	}
+
+	for _, list := range dec.AccessLists {
+		if list == nil {
+			t.AccessLists = append(t.AccessLists, nil)
+			continue
+		}
+		accessList := state.AccessList{}
+		for _, tuple := range *list {
+			entry := state.AccessTuple{
+				Address:     types.StringToAddress(tuple.Address),
+				StorageKeys: []types.Hash{},
+			}
+			for _, key := range tuple.StorageKeys {
+				entry.StorageKeys = append(entry.StorageKeys, types.StringToHash(key))
+			}
+			accessList = append(accessList, entry)
+		}
+		t.AccessLists = append(t.AccessLists, accessList)
+	}
+
+	if dec.BlobFeeCap != "" {
+		if t.BlobFeeCap, err = stringToBigInt(dec.BlobFeeCap); err != nil {
+			return err
+		}
+		for _, hash := range dec.BlobHashes {
+			t.BlobHashes = append(t.BlobHashes, types.StringToHash(hash))
+		}
+	}
+
+	if dec.AuthList != nil {
+		t.AuthList = []state.SetCodeAuthorization{}
+	}
+	for _, auth := range dec.AuthList {
+		entry := state.SetCodeAuthorization{
+			Address: types.StringToAddress(auth.Address),
+		}
+		if entry.ChainID, err = stringToBigInt(auth.ChainID); err != nil {
+			return err
+		}
+		if entry.Nonce, err = stringToUint64(auth.Nonce); err != nil {
+			return err
+		}
+		v, err := stringToUint64(auth.V)
+		if err != nil {
+			return err
+		}
+		entry.V = byte(v)
+		if entry.R, err = stringToBigInt(auth.R); err != nil {
+			return err
+		}
+		if entry.S, err = stringToBigInt(auth.S); err != nil {
+			return err
+		}
+		t.AuthList = append(t.AuthList, entry)
+	}
 	return nil
 }
 
@@ -403,6 +531,104 @@ var Forks = map[string]*runtime.Forks{
 		Petersburg:     runtime.NewFork(0),
 		Istanbul:       runtime.NewFork(0),
 	},
+	"Berlin": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+	},
+	"London": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+	},
+	"Paris": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+		Paris:          runtime.NewFork(0),
+	},
+	"Shanghai": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+		Paris:          runtime.NewFork(0),
+		Shanghai:       runtime.NewFork(0),
+	},
+	"Cancun": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+		Paris:          runtime.NewFork(0),
+		Shanghai:       runtime.NewFork(0),
+		Cancun:         runtime.NewFork(0),
+	},
+	"Prague": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+		Paris:          runtime.NewFork(0),
+		Shanghai:       runtime.NewFork(0),
+		Cancun:         runtime.NewFork(0),
+		Prague:         runtime.NewFork(0),
+	},
+	"Osaka": {
+		Homestead:      runtime.NewFork(0),
+		EIP150:         runtime.NewFork(0),
+		EIP155:         runtime.NewFork(0),
+		EIP158:         runtime.NewFork(0),
+		Byzantium:      runtime.NewFork(0),
+		Constantinople: runtime.NewFork(0),
+		Petersburg:     runtime.NewFork(0),
+		Istanbul:       runtime.NewFork(0),
+		Berlin:         runtime.NewFork(0),
+		London:         runtime.NewFork(0),
+		Paris:          runtime.NewFork(0),
+		Shanghai:       runtime.NewFork(0),
+		Cancun:         runtime.NewFork(0),
+		Prague:         runtime.NewFork(0),
+		Osaka:          runtime.NewFork(0),
+	},
 	"FrontierToHomesteadAt5": {
 		Homestead: runtime.NewFork(5),
 	},
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"math/big"
@@ -13,15 +14,69 @@ import (
 )
 
 const (
-	spuriousDragonMaxCodeSize = 24576
-
 	// Per transaction not creating a contract
 	TxGas uint64 = 21000
 
 	// Per transaction that creates a contract
 	TxGasContractCreation uint64 = 53000
+
+	// Per address in the access list (eip-2930)
+	TxAccessListAddressGas uint64 = 2400
+
+	// Per storage key in the access list (eip-2930)
+	TxAccessListStorageKeyGas uint64 = 1900
+
+	// Blob gas used by each blob of the transaction (eip-4844)
+	BlobTxBlobGasPerBlob uint64 = 1 << 17
+
+	// Version byte of the blob versioned hashes (eip-4844)
+	BlobTxHashVersion = 0x01
+
+	// Minimum of the blob base fee (eip-4844)
+	BlobTxMinBlobGasPrice = 1
+
+	// Per authorization in the set code transaction (eip-7702)
+	TxAuthEmptyAccountGas uint64 = 25000
+
+	// Cost of an authorization of an existing account (eip-7702)
+	TxAuthBaseGas uint64 = 12500
+
+	// Per token of calldata for the floor of the gas used (eip-7623)
+	TxCostFloorPerToken uint64 = 10
+
+	// Maximum gas limit of a transaction (eip-7825)
+	MaxTxGas uint64 = 1 << 24
+
+	// Gas available for the system calls
+	SystemCallGas uint64 = 30000000
+
+	// Number of block hashes kept in the history storage contract (eip-2935)
+	HistoryServeWindow = 8191
+)
+
+var (
+	// SystemAddress is the caller of the system calls
+	SystemAddress = types.StringToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
+
+	// BeaconRootsAddress is the address of the beacon roots contract (eip-4788)
+	BeaconRootsAddress = types.StringToAddress("0x000F3df6D732807Ef1319fB7B8bB8522d0Beac02")
+
+	// HistoryStorageAddress is the address of the history storage contract (eip-2935)
+	HistoryStorageAddress = types.StringToAddress("0x0000F90827F1C53a10cb7A02335B175320002935")
+
+	// DepositContractAddress is the address of the deposit contract in mainnet (eip-6110)
+	DepositContractAddress = types.StringToAddress("0x00000000219ab540356cBB839Cbe05303d7705Fa")
+
+	// WithdrawalRequestAddress is the address of the withdrawal requests contract (eip-7002)
+	WithdrawalRequestAddress = types.StringToAddress("0x00000961Ef480Eb55e80D19ad83579A64c007002")
+
+	// ConsolidationRequestAddress is the address of the consolidation requests contract (eip-7251)
+	ConsolidationRequestAddress = types.StringToAddress("0x0000BBdDc7CE488642fb579F8B00f3a590007251")
 )
 
+// depositEventTopic is the topic of the DepositEvent log of the deposit contract
+var depositEventTopic = types.StringToHash("0x649bbc62d0e31342afea4e5cd82d4049e7e1ee912fc0889aa790803be39038c5")
+
 var emptyCodeHashTwo = types.BytesToHash(helper.Keccak256(nil))
 
 // GetHashByNumber returns the hash function of a block number
@@ -32,9 +87,15 @@ type GetHashByNumberHelper = func(num uint64, hash types.Hash) GetHashByNumber
 type Transition struct {
 	runtimes []runtime.Runtime
 
+	// precompiles is the runtime for the precompiled contracts
+	precompiles *precompiled.Precompiled
+
 	// forks are the enabled forks for this transition
 	forks runtime.ForksInTime
 
+	// limits are the protocol limits of the chain
+	limits runtime.Limits
+
 	// txn is the transaction of changes
 	txn *Txn
 
@@ -49,6 +110,18 @@ type Transition struct {
 
 	// counter on the total gas used so far
 	totalGas uint64
+
+	// receipts of the transactions written so far
+	receipts []*Result
+
+	// requests of the block (eip-7685)
+	requests []*Request
+
+	// depositContract is the address that emits the deposit logs (eip-6110)
+	depositContract types.Address
+
+	// skipSenderCodeCheck accepts transactions from senders with code (eip-3607)
+	skipSenderCodeCheck bool
 }
 
 // NewExecutor creates a new executor
@@ -59,12 +132,22 @@ func NewTransition(forks runtime.ForksInTime, ctx runtime.TxContext, snap Snapsh
 		ctx:      ctx,
 		txn:      txn,
 		forks:    forks,
+		limits:   forks.GetLimits(),
 		gasPool:  uint64(ctx.GasLimit),
 		totalGas: 0,
+
+		depositContract: DepositContractAddress,
+	}
+
+	if forks.Enabled(runtime.EIP7516) && ctx.BlobBaseFee == (types.Hash{}) {
+		fraction := forks.GetBlobConfig().UpdateFraction
+		transition.ctx.BlobBaseFee = types.BytesToHash(CalcBlobFee(ctx.ExcessBlobGas, fraction).Bytes())
 	}
 
+	transition.precompiles = precompiled.NewPrecompiled()
+
 	transition.SetRuntime(evm.NewEVM())
-	transition.SetRuntime(precompiled.NewPrecompiled())
+	transition.SetRuntime(transition.precompiles)
 
 	// by default for getHash use a simple one
 	transition.getHash = func(n uint64) types.Hash {
@@ -74,10 +157,206 @@ func NewTransition(forks runtime.ForksInTime, ctx runtime.TxContext, snap Snapsh
 	return transition
 }
 
+// ProcessIrregular applies the irregular state changes of the block that
+// go before the first transaction. It must be called once per block.
+func (t *Transition) ProcessIrregular() {
+	t.applyIrregular(false)
+}
+
+// ProcessIrregularEnd applies the irregular state changes of the block that
+// go after the last transaction. It must be called once per block.
+func (t *Transition) ProcessIrregularEnd() {
+	t.applyIrregular(true)
+}
+
+// applyIrregular applies the irregular state changes of the block either
+// at the start or at the end. The balances are moved first and then the
+// accounts are replaced.
+func (t *Transition) applyIrregular(atEnd bool) {
+	for _, change := range t.forks.Irregular {
+		if change.AtEnd != atEnd {
+			continue
+		}
+		for _, move := range change.Moves {
+			for _, from := range move.From {
+				balance := new(big.Int).Set(t.txn.GetBalance(from))
+				t.txn.SetBalance(from, big.NewInt(0))
+				t.txn.AddBalance(move.To, balance)
+			}
+		}
+
+		for addr, account := range change.Accounts {
+			if account.Code != nil {
+				t.txn.SetCode(addr, account.Code)
+			}
+			if account.Balance != nil {
+				t.txn.SetBalance(addr, account.Balance)
+			}
+			for key, value := range account.Storage {
+				t.txn.SetState(addr, key, value)
+			}
+		}
+	}
+}
+
 func (e *Transition) Commit() []*Object {
 	return e.txn.Commit()
 }
 
+// AddSealingReward pays the block or uncle reward to the address.
+// There are no rewards after the merge.
+func (t *Transition) AddSealingReward(addr types.Address, balance *big.Int) error {
+	if t.forks.Enabled(runtime.EIP3675) {
+		return ErrRewardAfterMerge
+	}
+
+	t.txn.AddSealingReward(addr, balance)
+	return nil
+}
+
+// ProcessWithdrawals credits the withdrawals of the block to their
+// recipients (eip-4895). It has to be called after all the transactions
+// of the block are written and before Commit.
+func (t *Transition) ProcessWithdrawals(withdrawals []*Withdrawal) error {
+	if !t.forks.Enabled(runtime.EIP4895) {
+		return ErrWithdrawalsNotEnabled
+	}
+
+	for _, w := range withdrawals {
+		t.txn.AddBalance(w.Address, w.AmountWei())
+	}
+
+	// withdrawals with zero amount touch the account
+	t.txn.CleanDeleteObjects(t.forks.Enabled(runtime.EIP161))
+	return nil
+}
+
+// ProcessBeaconBlockRoot stores the parent beacon block root of the
+// context in the beacon roots contract (eip-4788). It has to be called
+// before any transaction of the block is written.
+func (t *Transition) ProcessBeaconBlockRoot() error {
+	if !t.forks.Enabled(runtime.EIP4788) {
+		return ErrBeaconRootNotEnabled
+	}
+
+	t.systemCall(BeaconRootsAddress, t.ctx.ParentBeaconRoot.Bytes())
+	return nil
+}
+
+// SetDepositContract sets the address of the deposit contract of the chain
+func (t *Transition) SetDepositContract(addr types.Address) {
+	t.depositContract = addr
+}
+
+// ProcessRequests collects the execution layer requests of the block
+// (eip-7685). The deposits are parsed from the logs of the receipts
+// (eip-6110) and the withdrawal (eip-7002) and consolidation (eip-7251)
+// requests are dequeued from their contracts. It has to be called after
+// all the transactions of the block are written.
+func (t *Transition) ProcessRequests() ([]*Request, error) {
+	if !t.forks.Enabled(runtime.EIP7685) {
+		return nil, ErrRequestsNotEnabled
+	}
+
+	deposits := &Request{Type: DepositRequestType}
+	for _, receipt := range t.receipts {
+		for _, log := range receipt.Logs {
+			if log.Address != t.depositContract || len(log.Topics) == 0 || log.Topics[0] != depositEventTopic {
+				continue
+			}
+			data, err := parseDepositLog(log.Data)
+			if err != nil {
+				return nil, err
+			}
+			deposits.Data = append(deposits.Data, data...)
+		}
+	}
+
+	withdrawals, err := t.requestsSystemCall(WithdrawalRequestType, WithdrawalRequestAddress)
+	if err != nil {
+		return nil, err
+	}
+	consolidations, err := t.requestsSystemCall(ConsolidationRequestType, ConsolidationRequestAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	t.requests = []*Request{deposits, withdrawals, consolidations}
+	return t.requests, nil
+}
+
+// requestsSystemCall dequeues the requests of the contract. Unlike the other
+// system calls, the contract must have code and the call must succeed.
+func (t *Transition) requestsSystemCall(typ RequestType, addr types.Address) (*Request, error) {
+	if t.txn.GetCodeSize(addr) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRequestsNoCode, addr)
+	}
+
+	result := t.systemCall(addr, nil)
+	if result.Failed() {
+		return nil, fmt.Errorf("%w: %v", ErrRequestsCallFailed, result.Err)
+	}
+	return &Request{Type: typ, Data: result.ReturnValue}, nil
+}
+
+// depositLogLayout are the abi offsets and sizes of the pubkey, withdrawal
+// credentials, amount, signature and index fields of the DepositEvent log
+var depositLogLayout = [5][2]uint64{{160, 48}, {256, 32}, {320, 8}, {384, 96}, {512, 8}}
+
+// parseDepositLog returns the deposit request of the DepositEvent log data
+func parseDepositLog(data []byte) ([]byte, error) {
+	if len(data) != 576 {
+		return nil, ErrInvalidDepositLog
+	}
+
+	request := make([]byte, 0, 192)
+	for i, field := range depositLogLayout {
+		offset, size := field[0], field[1]
+
+		if !bytes.Equal(data[i*32:(i+1)*32], abiWord(offset)) || !bytes.Equal(data[offset:offset+32], abiWord(size)) {
+			return nil, ErrInvalidDepositLog
+		}
+		request = append(request, data[offset+32:offset+32+size]...)
+	}
+	return request, nil
+}
+
+func abiWord(n uint64) []byte {
+	return types.BytesToHash(new(big.Int).SetUint64(n).Bytes()).Bytes()
+}
+
+// systemCall calls the contract from the system address. The call does not
+// pay for gas, does not use the gas pool and does not increase any nonce.
+// It is a noop if the contract has no code.
+func (t *Transition) systemCall(to types.Address, input []byte) *runtime.ExecutionResult {
+	if t.txn.GetCodeSize(to) == 0 {
+		return nil
+	}
+
+	// the call runs with the context of the system and the context of
+	// the block is restored after it
+	ctx := t.ctx
+	defer func() {
+		t.ctx = ctx
+	}()
+
+	t.ctx.Origin = SystemAddress
+	t.ctx.GasPrice = types.Hash{}
+	t.ctx.BlobHashes = nil
+
+	if t.forks.Enabled(runtime.EIP2929) {
+		t.txn.AddAddressToAccessList(to)
+	}
+
+	result := t.Call(SystemAddress, to, input, big.NewInt(0), SystemCallGas)
+
+	// the logs of the call are not part of any receipt
+	t.txn.Logs()
+	t.txn.CleanDeleteObjects(t.forks.Enabled(runtime.EIP161))
+
+	return result
+}
+
 func (t *Transition) TotalGas() uint64 {
 	return t.totalGas
 }
@@ -90,12 +369,60 @@ type BlockResult struct {
 	Root     types.Hash
 	Receipts []*Result
 	TotalGas uint64
+
+	// Requests are the execution layer requests of the block and
+	// RequestsHash their commitment (eip-7685)
+	Requests     []*Request
+	RequestsHash types.Hash
+}
+
+// BlockResult returns the receipts and requests of the block. The requests
+// are only set if ProcessRequests was called before.
+func (t *Transition) BlockResult() *BlockResult {
+	res := &BlockResult{
+		Receipts: t.receipts,
+		TotalGas: t.totalGas,
+	}
+	if t.forks.Enabled(runtime.EIP7685) {
+		res.Requests = t.requests
+		res.RequestsHash = RequestsHash(t.requests)
+	}
+	return res
 }
 
 func (t *Transition) SetGetHash(helper GetHashByNumberHelper) {
 	t.getHash = helper(uint64(t.ctx.Number), t.ctx.Hash)
 }
 
+// SetGetHashFromState serves the block hashes from the history
+// storage contract (eip-2935) instead of a GetHashByNumber helper
+func (t *Transition) SetGetHashFromState() {
+	t.getHash = func(n uint64) types.Hash {
+		return t.txn.GetState(HistoryStorageAddress, historyStorageSlot(n))
+	}
+}
+
+// ProcessParentBlockHash stores the hash of the parent block in the
+// history storage contract (eip-2935) with a system call. It has to be
+// called before any transaction of the block is written.
+func (t *Transition) ProcessParentBlockHash(parent types.Hash) error {
+	if !t.forks.Enabled(runtime.EIP2935) {
+		return ErrHistoryNotEnabled
+	}
+	if t.ctx.Number == 0 {
+		// the genesis block has no parent
+		return nil
+	}
+
+	t.systemCall(HistoryStorageAddress, parent.Bytes())
+	return nil
+}
+
+// historyStorageSlot returns the slot of the ring buffer for the block number
+func historyStorageSlot(number uint64) types.Hash {
+	return types.BytesToHash(new(big.Int).SetUint64(number % HistoryServeWindow).Bytes())
+}
+
 func (t *Transition) subGasPool(amount uint64) error {
 	if t.gasPool < amount {
 		return ErrBlockLimitReached
@@ -134,7 +461,7 @@ func (t *Transition) Write(txn *Transaction) (*Result, error) {
 		ReturnValue: result.ReturnValue,
 	}
 
-	if t.forks.Byzantium {
+	if t.forks.Enabled(runtime.EIP658) {
 		// The suicided accounts are set as deleted for the next iteration
 		t.txn.CleanDeleteObjects(true)
 
@@ -146,7 +473,7 @@ func (t *Transition) Write(txn *Transaction) (*Result, error) {
 
 	} else {
 		// TODO: If byzntium is enabled you need a special step to commit the data yourself
-		t.txn.CleanDeleteObjects(t.forks.EIP158)
+		t.txn.CleanDeleteObjects(t.forks.Enabled(runtime.EIP161))
 
 		/*
 			objs := t.txn.Commit(t.forks.EIP155)
@@ -166,6 +493,7 @@ func (t *Transition) Write(txn *Transaction) (*Result, error) {
 	// Set the receipt logs and create a bloom for filtering
 	receipt.Logs = logs
 
+	t.receipts = append(t.receipts, receipt)
 	return receipt, nil
 }
 
@@ -184,6 +512,26 @@ func (t *Transition) subGasLimitPrice(msg *Transaction) error {
 	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
 	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
 
+	if msg.Type == BlobTx {
+		// the blob fee is paid upfront and burned (eip-4844)
+		blobGas := new(big.Int).SetUint64(msg.BlobGas())
+		upfrontGasCost.Add(upfrontGasCost, new(big.Int).Mul(blobGas, t.blobBaseFee()))
+	}
+
+	if msg.GasFeeCap != nil {
+		// the balance has to cover the max fee and the value even if
+		// only the effective gas price is deducted (eip-1559)
+		maxGasCost := new(big.Int).Mul(msg.GasFeeCap, new(big.Int).SetUint64(msg.Gas))
+		maxGasCost.Add(maxGasCost, msg.Value)
+		if msg.Type == BlobTx {
+			maxGasCost.Add(maxGasCost, new(big.Int).Mul(new(big.Int).SetUint64(msg.BlobGas()), msg.BlobFeeCap))
+		}
+
+		if balance := t.txn.GetBalance(msg.From); balance.Cmp(maxGasCost) < 0 {
+			return ErrNotEnoughFundsForGas
+		}
+	}
+
 	if err := t.txn.SubBalance(msg.From, upfrontGasCost); err != nil {
 		if err == runtime.ErrNotEnoughFunds {
 			return ErrNotEnoughFundsForGas
@@ -193,6 +541,162 @@ func (t *Transition) subGasLimitPrice(msg *Transaction) error {
 	return nil
 }
 
+func (t *Transition) txTypeCheck(msg *Transaction) error {
+	switch msg.Type {
+	case LegacyTx:
+		return nil
+	case AccessListTx:
+		if t.forks.Enabled(runtime.EIP2930) {
+			return nil
+		}
+	case DynamicFeeTx:
+		if t.forks.Enabled(runtime.EIP1559) {
+			return nil
+		}
+	case BlobTx:
+		if t.forks.Enabled(runtime.EIP4844) {
+			return nil
+		}
+	case SetCodeTx:
+		if t.forks.Enabled(runtime.EIP7702) {
+			return nil
+		}
+	}
+	return ErrTxTypeNotSupported
+}
+
+// baseFee returns the base fee of the block (eip-1559)
+func (t *Transition) baseFee() *big.Int {
+	return new(big.Int).SetBytes(t.ctx.BaseFee.Bytes())
+}
+
+func (t *Transition) feeCheck(msg *Transaction) error {
+	feeCap := msg.GetGasFeeCap()
+
+	if feeCap.Cmp(msg.GetGasTipCap()) < 0 {
+		return ErrTipAboveFeeCap
+	}
+	if feeCap.Cmp(t.baseFee()) < 0 {
+		return ErrFeeCapTooLow
+	}
+	return nil
+}
+
+// blobBaseFee returns the blob base fee of the block (eip-4844)
+func (t *Transition) blobBaseFee() *big.Int {
+	return new(big.Int).SetBytes(t.ctx.BlobBaseFee.Bytes())
+}
+
+func (t *Transition) blobCheck(msg *Transaction) error {
+	if msg.To == nil {
+		return ErrBlobTxCreate
+	}
+	if len(msg.BlobHashes) == 0 {
+		return ErrMissingBlobHashes
+	}
+	if uint64(len(msg.BlobHashes)) > t.forks.GetBlobConfig().Max {
+		return ErrTooManyBlobs
+	}
+	for _, hash := range msg.BlobHashes {
+		if hash[0] != BlobTxHashVersion {
+			return ErrBlobInvalidVersion
+		}
+	}
+	if msg.BlobFeeCap == nil || msg.BlobFeeCap.Cmp(t.blobBaseFee()) < 0 {
+		return ErrBlobFeeCapTooLow
+	}
+	return nil
+}
+
+// CalcBlobFee returns the blob base fee for the excess blob gas and the
+// update fraction of the fork (eip-4844)
+func CalcBlobFee(excessBlobGas, updateFraction uint64) *big.Int {
+	return fakeExponential(big.NewInt(BlobTxMinBlobGasPrice), new(big.Int).SetUint64(excessBlobGas), new(big.Int).SetUint64(updateFraction))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using Taylor expansion
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+	return output.Div(output, denominator)
+}
+
+// applyAuthorization delegates the code of the authority to the
+// address of the authorization (eip-7702)
+func (t *Transition) applyAuthorization(auth *SetCodeAuthorization) error {
+	// 1. the chain id is either zero or the chain id of the chain
+	if !isUint256(auth.ChainID) {
+		return ErrAuthorizationInvalidChainID
+	}
+	if auth.ChainID.Sign() != 0 && auth.ChainID.Cmp(big.NewInt(t.ctx.ChainID)) != 0 {
+		return ErrAuthorizationWrongChainID
+	}
+
+	// 2. the nonce of the authority can be incremented
+	if auth.Nonce+1 < auth.Nonce {
+		return ErrAuthorizationNonceOverflow
+	}
+
+	// 3. the signature is valid
+	authority, err := auth.Authority()
+	if err != nil {
+		return ErrAuthorizationInvalidSignature
+	}
+
+	// 4. the authority is warm
+	t.txn.AddAddressToAccessList(authority)
+
+	// 5. the authority has no code or it is already delegated
+	code := t.txn.GetCode(authority)
+	if _, ok := runtime.ParseDelegation(code); len(code) != 0 && !ok {
+		return ErrAuthorizationDestinationHasCode
+	}
+
+	// 6. the nonce matches the one of the authority
+	if t.txn.GetNonce(authority) != auth.Nonce {
+		return ErrAuthorizationNonceMismatch
+	}
+
+	// the intrinsic gas assumes an empty account
+	if t.txn.Exist(authority) {
+		t.txn.AddRefund(TxAuthEmptyAccountGas - TxAuthBaseGas)
+	}
+
+	t.txn.IncrNonce(authority)
+
+	if auth.Address == types.ZeroAddress {
+		// delegations to the zero address clear the code
+		t.txn.SetCode(authority, nil)
+	} else {
+		t.txn.SetCode(authority, runtime.AddressToDelegation(auth.Address))
+	}
+	return nil
+}
+
+// SetSkipSenderCodeCheck enables or disables the rejection of the transactions
+// whose sender has code (eip-3607), i.e. to simulate calls from contracts
+func (t *Transition) SetSkipSenderCodeCheck(skip bool) {
+	t.skipSenderCodeCheck = skip
+}
+
+func (t *Transition) senderCodeCheck(msg *Transaction) error {
+	code := t.txn.GetCode(msg.From)
+	if len(code) == 0 {
+		return nil
+	}
+	if _, ok := runtime.ParseDelegation(code); ok {
+		return nil
+	}
+	return ErrSenderNoEOA
+}
+
 func (t *Transition) nonceCheck(msg *Transaction) error {
 	nonce := t.txn.GetNonce(msg.From)
 
@@ -210,46 +714,140 @@ var (
 	ErrIntrinsicGasOverflow  = fmt.Errorf("overflow in intrinsic gas calculation")
 	ErrNotEnoughIntrinsicGas = fmt.Errorf("not enough gas supplied for intrinsic gas costs")
 	ErrNotEnoughFunds        = fmt.Errorf("not enough funds for transfer with given value")
+	ErrTxTypeNotSupported    = fmt.Errorf("transaction type not supported")
+	ErrTipAboveFeeCap        = fmt.Errorf("max priority fee per gas higher than max fee per gas")
+	ErrFeeCapTooLow          = fmt.Errorf("max fee per gas less than block base fee")
+	ErrWithdrawalsNotEnabled = fmt.Errorf("withdrawals are not enabled before shanghai")
+	ErrBeaconRootNotEnabled  = fmt.Errorf("beacon block root is not enabled before cancun")
+	ErrRewardAfterMerge      = fmt.Errorf("block rewards are not paid after the merge")
+	ErrHistoryNotEnabled     = fmt.Errorf("history storage is not enabled before prague")
+	ErrRequestsNotEnabled    = fmt.Errorf("execution layer requests are not enabled before prague")
+	ErrInvalidDepositLog     = fmt.Errorf("invalid deposit log data")
+	ErrRequestsNoCode        = fmt.Errorf("requests contract has no code")
+	ErrRequestsCallFailed    = fmt.Errorf("requests system call failed")
+	ErrFloorDataGas          = fmt.Errorf("insufficient gas for floor data gas cost")
+	ErrGasLimitTooHigh       = fmt.Errorf("transaction gas limit above the cap")
+	ErrSenderNoEOA           = fmt.Errorf("sender not an eoa")
+	ErrBlobTxCreate          = fmt.Errorf("blob transaction of type create")
+	ErrMissingBlobHashes     = fmt.Errorf("blob transaction missing blob hashes")
+	ErrTooManyBlobs          = fmt.Errorf("blob transaction with more blobs than the block maximum")
+	ErrBlobInvalidVersion    = fmt.Errorf("blob hash with invalid version")
+	ErrBlobFeeCapTooLow      = fmt.Errorf("max fee per blob gas less than block blob base fee")
+	ErrSetCodeTxCreate       = fmt.Errorf("set code transaction of type create")
+	ErrEmptyAuthList         = fmt.Errorf("set code transaction with empty auth list")
+
+	// errors of the authorizations (eip-7702). Invalid authorizations are skipped
+	ErrAuthorizationWrongChainID       = fmt.Errorf("authorization chain id mismatch")
+	ErrAuthorizationInvalidChainID     = fmt.Errorf("authorization has invalid chain id")
+	ErrAuthorizationNonceOverflow      = fmt.Errorf("authorization nonce too high")
+	ErrAuthorizationInvalidSignature   = fmt.Errorf("authorization has invalid signature")
+	ErrAuthorizationDestinationHasCode = fmt.Errorf("authorization destination has code")
+	ErrAuthorizationNonceMismatch      = fmt.Errorf("authorization nonce does not match current account nonce")
 )
 
 func (t *Transition) apply(msg *Transaction) (*runtime.ExecutionResult, error) {
 	txn := t.txn
 
 	gasLeft := uint64(0)
+	floorDataGas := uint64(0)
 
 	// First check this message satisfies all consensus rules before
 	// applying the message.
 	preCheck := func() error {
+		// 0. the type of the transaction is enabled in the current fork
+		if err := t.txTypeCheck(msg); err != nil {
+			return err
+		}
+
 		// 1. the nonce of the message caller is correct
 		if err := t.nonceCheck(msg); err != nil {
 			return err
 		}
 
-		// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
-		if err := t.subGasLimitPrice(msg); err != nil {
-			return err
+		// 2. the sender is not a contract (eip-3607). Accounts with a
+		// delegation (eip-7702) can still send transactions
+		if !t.skipSenderCodeCheck {
+			if err := t.senderCodeCheck(msg); err != nil {
+				return err
+			}
 		}
 
-		// 3. the amount of gas required is available in the block
-		if err := t.subGasPool(msg.Gas); err != nil {
-			return err
+		// 3. the gas limit of the transaction is below the cap (eip-7825)
+		if t.forks.Enabled(runtime.EIP7825) && msg.Gas > MaxTxGas {
+			return ErrGasLimitTooHigh
+		}
+
+		// 4. the fee caps cover the base fee of the block and the gas price
+		// is the effective one after the base fee (eip-1559)
+		if t.forks.Enabled(runtime.EIP1559) {
+			if err := t.feeCheck(msg); err != nil {
+				return err
+			}
+			msg.GasPrice = msg.EffectiveGasPrice(t.baseFee())
+		}
+
+		// 5. the blob transaction is valid and the blob fee cap covers the
+		// blob base fee of the block (eip-4844)
+		if msg.Type == BlobTx {
+			if err := t.blobCheck(msg); err != nil {
+				return err
+			}
+		}
+
+		// 6. the set code transaction has a recipient and authorizations (eip-7702)
+		if msg.Type == SetCodeTx {
+			if msg.To == nil {
+				return ErrSetCodeTxCreate
+			}
+			if len(msg.AuthList) == 0 {
+				return ErrEmptyAuthList
+			}
 		}
 
-		// 4. there is no overflow when calculating intrinsic gas
-		intrinsicGasCost, err := TransactionGasCost(msg, t.forks.Homestead, t.forks.Istanbul)
+		// 7. the initcode is within the limit (eip-3860) and there is no
+		// overflow when calculating intrinsic gas
+		if msg.IsContractCreation() && t.forks.Enabled(runtime.EIP3860) && len(msg.Input) > t.limits.MaxInitCodeSize {
+			return runtime.ErrMaxInitCodeSizeExceeded
+		}
+		intrinsicGasCost, err := TransactionGasCost(msg, t.forks.Enabled(runtime.EIP2), t.forks.Enabled(runtime.EIP2028), t.forks.Enabled(runtime.EIP3860))
 		if err != nil {
 			return err
 		}
 
-		// 5. the purchased gas is enough to cover intrinsic usage
+		// 8. the purchased gas is enough to cover intrinsic usage
 		gasLeft = msg.Gas - intrinsicGasCost
 		// Because we are working with unsigned integers for gas, the `>` operator is used instead of the more intuitive `<`
 		if gasLeft > msg.Gas {
 			return ErrNotEnoughIntrinsicGas
 		}
 
-		// 6. caller has enough balance to cover asset transfer for **topmost** call
+		// 9. the purchased gas is enough to cover the calldata floor (eip-7623)
+		if t.forks.Enabled(runtime.EIP7623) {
+			if floorDataGas, err = FloorDataGas(msg); err != nil {
+				return err
+			}
+			if msg.Gas < floorDataGas {
+				return ErrFloorDataGas
+			}
+		}
+
+		// 10. caller has enough balance to cover transaction fee(gaslimit * gasprice).
+		// The fee is restored if any of the next checks fails
+		snapshot := txn.Snapshot()
+		if err := t.subGasLimitPrice(msg); err != nil {
+			return err
+		}
+
+		// 11. the amount of gas required is available in the block
+		if err := t.subGasPool(msg.Gas); err != nil {
+			txn.RevertToSnapshot(snapshot)
+			return err
+		}
+
+		// 12. caller has enough balance to cover asset transfer for **topmost** call
 		if balance := txn.GetBalance(msg.From); balance.Cmp(msg.Value) < 0 {
+			t.addGasPool(msg.Gas)
+			txn.RevertToSnapshot(snapshot)
 			return ErrNotEnoughFunds
 		}
 		return nil
@@ -265,12 +863,31 @@ func (t *Transition) apply(msg *Transaction) (*runtime.ExecutionResult, error) {
 	// Override the context and set the specific transaction fields
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = msg.From
+	t.ctx.BlobHashes = msg.BlobHashes
+
+	if t.forks.Enabled(runtime.EIP2929) {
+		t.prepareAccessList(msg)
+	}
 
 	var result *runtime.ExecutionResult = nil
 	if msg.IsContractCreation() {
 		result = t.Create(msg.From, msg.Input, value, gasLeft)
 	} else {
 		txn.IncrNonce(msg.From)
+
+		if msg.Type == SetCodeTx {
+			for i := range msg.AuthList {
+				// invalid authorizations are skipped
+				_ = t.applyAuthorization(&msg.AuthList[i])
+			}
+		}
+		if t.forks.Enabled(runtime.EIP7702) {
+			// the delegation target of the recipient is warm (eip-7702)
+			if target, ok := runtime.ParseDelegation(txn.GetCode(*msg.To)); ok {
+				txn.AddAddressToAccessList(target)
+			}
+		}
+
 		result = t.Call(msg.From, *msg.To, msg.Input, value, gasLeft)
 	}
 
@@ -278,30 +895,74 @@ func (t *Transition) apply(msg *Transaction) (*runtime.ExecutionResult, error) {
 	refund := txn.GetRefund()
 	{
 		result.GasUsed = msg.Gas - result.GasLeft
-		maxRefund := result.GasUsed / 2
 		// Refund can go up to half the gas used
+		maxRefund := result.GasUsed / 2
+		if t.forks.Enabled(runtime.EIP3529) {
+			// eip-3529: refund can go up to a fifth of the gas used
+			maxRefund = result.GasUsed / 5
+		}
 		if refund > maxRefund {
 			refund = maxRefund
 		}
 
 		result.GasLeft += refund
 		result.GasUsed -= refund
+
+		// the gas used is at least the calldata floor (eip-7623)
+		if result.GasUsed < floorDataGas {
+			result.GasUsed = floorDataGas
+			result.GasLeft = msg.Gas - floorDataGas
+		}
 	}
 
 	// refund the sender
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	txn.AddBalance(msg.From, remaining)
 
-	// pay the coinbase for the transaction
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
+	// pay the coinbase for the transaction. After london the base fee
+	// is burned and the coinbase only receives the tip (eip-1559)
+	effectiveTip := gasPrice
+	if t.forks.Enabled(runtime.EIP1559) {
+		effectiveTip = new(big.Int).Sub(gasPrice, t.baseFee())
+	}
+	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), effectiveTip)
 	txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
 
+	// bor pays the base fee to the burnt contract instead of burning it
+	if t.forks.BurntContract != nil && t.forks.Enabled(runtime.EIP1559) {
+		burntFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), t.baseFee())
+		txn.AddBalance(*t.forks.BurntContract, burntFee)
+	}
+
 	// return gas to the pool
 	t.addGasPool(result.GasLeft)
 
 	return result, nil
 }
 
+// prepareAccessList warms up the sender, the recipient, the precompiled
+// contracts (eip-2929) and the entries of the access list (eip-2930)
+// before the execution
+func (t *Transition) prepareAccessList(msg *Transaction) {
+	t.txn.AddAddressToAccessList(msg.From)
+	if msg.To != nil {
+		t.txn.AddAddressToAccessList(*msg.To)
+	}
+	if t.forks.Enabled(runtime.EIP3651) {
+		// eip-3651: warm coinbase
+		t.txn.AddAddressToAccessList(t.ctx.Coinbase)
+	}
+	for _, addr := range t.precompiles.Addresses(&t.forks) {
+		t.txn.AddAddressToAccessList(addr)
+	}
+	for _, tuple := range msg.AccessList {
+		t.txn.AddAddressToAccessList(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			t.txn.AddSlotToAccessList(tuple.Address, key)
+		}
+	}
+}
+
 func (t *Transition) Create(caller types.Address, code []byte, value *big.Int, gas uint64) *runtime.ExecutionResult {
 	address := helper.CreateAddress(caller, t.txn.GetNonce(caller))
 	contract := runtime.NewContractCreation(1, caller, caller, address, value, gas, code)
@@ -312,7 +973,15 @@ func (t *Transition) Create(caller types.Address, code []byte, value *big.Int, g
 }
 
 func (t *Transition) Call(caller types.Address, to types.Address, input []byte, value *big.Int, gas uint64) *runtime.ExecutionResult {
-	c := runtime.NewContractCall(1, caller, caller, to, value, gas, t.txn.GetCode(to), input)
+	code := t.txn.GetCode(to)
+	if t.forks.Enabled(runtime.EIP7702) {
+		// execute the code of the delegation target (eip-7702)
+		if target, ok := runtime.ParseDelegation(code); ok {
+			code = t.txn.GetCode(target)
+		}
+	}
+
+	c := runtime.NewContractCall(1, caller, caller, to, value, gas, code, input)
 	return t.applyCall(c, runtime.Call, t)
 }
 
@@ -345,7 +1014,7 @@ func (t *Transition) transfer(from, to types.Address, amount *big.Int) error {
 }
 
 func (t *Transition) applyCall(c *runtime.Contract, callType runtime.CallType, host runtime.Host) *runtime.ExecutionResult {
-	if c.Depth > int(1024)+1 {
+	if c.Depth > t.limits.MaxCallDepth+1 {
 		return &runtime.ExecutionResult{
 			GasLeft: c.Gas,
 			Err:     runtime.ErrDepth,
@@ -390,7 +1059,7 @@ func (t *Transition) hasCodeOrNonce(addr types.Address) bool {
 func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtime.ExecutionResult {
 	gasLimit := c.Gas
 
-	if c.Depth > int(1024)+1 {
+	if c.Depth > t.limits.MaxCallDepth+1 {
 		return &runtime.ExecutionResult{
 			GasLeft: gasLimit,
 			Err:     runtime.ErrDepth,
@@ -400,6 +1069,11 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 	// Increment the nonce of the caller
 	t.txn.IncrNonce(c.Caller)
 
+	if t.forks.Enabled(runtime.EIP2929) {
+		// the address of the new contract is always warm (eip-2929)
+		t.txn.AddAddressToAccessList(c.Address)
+	}
+
 	// Check if there if there is a collision and the address already exists
 	if t.hasCodeOrNonce(c.Address) {
 		return &runtime.ExecutionResult{
@@ -411,12 +1085,17 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 	// Take snapshot of the current state
 	snapshot := t.txn.Snapshot()
 
-	if t.forks.EIP158 {
+	if t.forks.Enabled(runtime.EIP161) {
 		// Force the creation of the account
 		t.txn.CreateAccount(c.Address)
 		t.txn.IncrNonce(c.Address)
 	}
 
+	if t.forks.Enabled(runtime.EIP6780) {
+		// track the contract to allow selfdestruct in the same transaction (eip-6780)
+		t.txn.AddCreatedContract(c.Address)
+	}
+
 	// Transfer the value
 	if err := t.transfer(c.Caller, c.Address, c.Value); err != nil {
 		return &runtime.ExecutionResult{
@@ -432,8 +1111,8 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		return result
 	}
 
-	if t.forks.EIP158 && len(result.ReturnValue) > spuriousDragonMaxCodeSize {
-		// Contract size exceeds 'SpuriousDragon' size limit
+	if t.forks.Enabled(runtime.EIP170) && len(result.ReturnValue) > t.limits.MaxCodeSize {
+		// Contract size exceeds the code size limit
 		t.txn.RevertToSnapshot(snapshot)
 		return &runtime.ExecutionResult{
 			GasLeft: 0,
@@ -441,14 +1120,23 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
-	gasCost := uint64(len(result.ReturnValue)) * 200
+	if t.forks.Enabled(runtime.EIP3541) && len(result.ReturnValue) > 0 && result.ReturnValue[0] == 0xEF {
+		// eip-3541: reject new code starting with the 0xEF byte
+		t.txn.RevertToSnapshot(snapshot)
+		return &runtime.ExecutionResult{
+			GasLeft: 0,
+			Err:     runtime.ErrInvalidCode,
+		}
+	}
+
+	gasCost := uint64(len(result.ReturnValue)) * t.limits.CodeDepositGas
 
 	if result.GasLeft < gasCost {
 		result.Err = runtime.ErrCodeStoreOutOfGas
 		result.ReturnValue = nil
 
 		// Out of gas creating the contract
-		if t.forks.Homestead {
+		if t.forks.Enabled(runtime.EIP2) {
 			t.txn.RevertToSnapshot(snapshot)
 			result.GasLeft = 0
 		}
@@ -510,10 +1198,46 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 	return t.txn.GetNonce(addr)
 }
 
+func (t *Transition) AddressInAccessList(addr types.Address) bool {
+	return t.txn.AddressInAccessList(addr)
+}
+
+func (t *Transition) SlotInAccessList(addr types.Address, slot types.Hash) (bool, bool) {
+	return t.txn.SlotInAccessList(addr, slot)
+}
+
+func (t *Transition) AddAddressToAccessList(addr types.Address) {
+	t.txn.AddAddressToAccessList(addr)
+}
+
+func (t *Transition) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	t.txn.AddSlotToAccessList(addr, slot)
+}
+
+func (t *Transition) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	return t.txn.GetTransientStorage(addr, key)
+}
+
+func (t *Transition) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	t.txn.SetTransientStorage(addr, key, value)
+}
+
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
-	if !t.txn.HasSuicided(addr) {
+	// eip-3529: selfdestruct does not refund gas after london
+	if !t.forks.Enabled(runtime.EIP3529) && !t.txn.HasSuicided(addr) {
 		t.txn.AddRefund(24000)
 	}
+
+	// eip-6780: only contracts created in the same transaction are
+	// destroyed, otherwise the balance is sent to the beneficiary
+	if t.forks.Enabled(runtime.EIP6780) && !t.txn.IsCreatedContract(addr) {
+		if addr != beneficiary {
+			// the balance is always available
+			_ = t.transfer(addr, beneficiary, t.txn.GetBalance(addr))
+		}
+		return
+	}
+
 	t.txn.AddBalance(beneficiary, t.txn.GetBalance(addr))
 	t.txn.Suicide(addr)
 }
@@ -525,7 +1249,26 @@ func (t *Transition) Callx(c *runtime.Contract, h runtime.Host) *runtime.Executi
 	return t.applyCall(c, c.Type, h)
 }
 
-func TransactionGasCost(msg *Transaction, isHomestead, isIstanbul bool) (uint64, error) {
+// FloorDataGas returns the minimum gas used by the transaction
+// depending on its calldata (eip-7623)
+func FloorDataGas(msg *Transaction) (uint64, error) {
+	zeros := uint64(0)
+	for _, b := range msg.Input {
+		if b == 0 {
+			zeros++
+		}
+	}
+	nonZeros := uint64(len(msg.Input)) - zeros
+
+	// a zero byte is one token and a non zero byte four tokens
+	tokens := zeros + nonZeros*4
+	if (math.MaxUint64-TxGas)/TxCostFloorPerToken < tokens {
+		return 0, ErrIntrinsicGasOverflow
+	}
+	return TxGas + tokens*TxCostFloorPerToken, nil
+}
+
+func TransactionGasCost(msg *Transaction, isHomestead, isIstanbul, isShanghai bool) (uint64, error) {
 	cost := uint64(0)
 
 	// Contract creation is only paid on the homestead fork
@@ -563,5 +1306,40 @@ func TransactionGasCost(msg *Transaction, isHomestead, isIstanbul bool) (uint64,
 		cost += zeros * 4
 	}
 
+	if len(msg.AuthList) > 0 {
+		auths := uint64(len(msg.AuthList))
+		if (math.MaxUint64-cost)/TxAuthEmptyAccountGas < auths {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += auths * TxAuthEmptyAccountGas
+	}
+
+	if msg.IsContractCreation() && isShanghai {
+		// eip-3860: meter initcode
+		words := (uint64(len(payload)) + 31) / 32
+		if (math.MaxUint64-cost)/runtime.InitCodeWordGas < words {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += words * runtime.InitCodeWordGas
+	}
+
+	if len(msg.AccessList) > 0 {
+		addresses := uint64(len(msg.AccessList))
+		if (math.MaxUint64-cost)/TxAccessListAddressGas < addresses {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += addresses * TxAccessListAddressGas
+
+		storageKeys := uint64(msg.AccessList.StorageKeys())
+		if (math.MaxUint64-cost)/TxAccessListStorageKeyGas < storageKeys {
+			return 0, ErrIntrinsicGasOverflow
+		}
+
+		cost += storageKeys * TxAccessListStorageKeyGas
+	}
+
 	return cost, nil
 }
@@ -1,11 +1,15 @@
 package state
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"math/big"
 	"testing"
 
+	"github.com/0xPolygon/eth-state-transition/helper"
 	"github.com/0xPolygon/eth-state-transition/runtime"
 	"github.com/0xPolygon/eth-state-transition/types"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -146,3 +150,1049 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionGasCostAccessList(t *testing.T) {
+	msg := &Transaction{
+		To: &addr2,
+		AccessList: AccessList{
+			{Address: addr1, StorageKeys: []types.Hash{hash1, hash2}},
+			{Address: addr2},
+		},
+	}
+
+	cost, err := TransactionGasCost(msg, true, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+2*TxAccessListAddressGas+2*TxAccessListStorageKeyGas, cost)
+}
+
+func TestTransactionGasCostInitCode(t *testing.T) {
+	// 33 bytes of initcode are two words
+	msg := &Transaction{
+		Input: make([]byte, 33),
+	}
+
+	cost, err := TransactionGasCost(msg, true, true, false)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGasContractCreation+33*4, cost)
+
+	cost, err = TransactionGasCost(msg, true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGasContractCreation+33*4+2*runtime.InitCodeWordGas, cost)
+}
+
+func TestInitCodeLimit(t *testing.T) {
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	msg := &Transaction{
+		From:     addr1,
+		Gas:      300000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		Input:    make([]byte, runtime.MainnetLimits.MaxInitCodeSize+1),
+	}
+
+	forks := runtime.ForksInTime{Homestead: true, Istanbul: true, Shanghai: true}
+	ctx := runtime.TxContext{GasLimit: 300000}
+
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+	_, err := transition.Write(msg)
+	assert.Equal(t, runtime.ErrMaxInitCodeSizeExceeded, err)
+	assert.Equal(t, big.NewInt(1000000), transition.GetBalance(addr1))
+	assert.Equal(t, uint64(300000), transition.gasPool)
+
+	// the fee is restored when the value is not covered
+	msg.Input = nil
+	msg.Value = big.NewInt(1000000)
+	transition = NewTransition(forks, ctx, newStateWithPreState(preState))
+	_, err = transition.Write(msg)
+	assert.Equal(t, ErrNotEnoughFunds, err)
+	assert.Equal(t, big.NewInt(1000000), transition.GetBalance(addr1))
+	assert.Equal(t, uint64(300000), transition.gasPool)
+	msg.Value = big.NewInt(0)
+	msg.Input = make([]byte, runtime.MainnetLimits.MaxInitCodeSize+1)
+
+	// the chain raises the initcode limit
+	limits := runtime.MainnetLimits
+	limits.MaxInitCodeSize *= 2
+	forks.Limits = &limits
+
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+}
+
+func TestCodeSizeLimit(t *testing.T) {
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	// init code that returns 24577 zero bytes
+	msg := &Transaction{
+		From:     addr1,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		Input:    []byte{0x61, 0x60, 0x01, 0x60, 0x00, 0xF3},
+	}
+
+	forks := runtime.ForksInTime{Homestead: true, EIP158: true, Byzantium: true}
+	ctx := runtime.TxContext{GasLimit: 100000}
+
+	result, err := NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+
+	// the chain raises the code size limit and the code is free to deposit
+	limits := runtime.MainnetLimits
+	limits.MaxCodeSize *= 2
+	limits.CodeDepositGas = 0
+	forks.Limits = &limits
+
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+	result, err = transition.Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, transition.Txn().GetCode(result.ContractAddress), 24577)
+}
+
+func TestAccessListTransaction(t *testing.T) {
+	// addr2 is a precompile, use a regular account as the recipient
+	to := types.StringToAddress("a2")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	msg := &Transaction{
+		Type:     AccessListTx,
+		From:     addr1,
+		To:       &to,
+		Gas:      30000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		AccessList: AccessList{
+			{Address: to, StorageKeys: []types.Hash{hash1}},
+		},
+	}
+
+	// access list transactions are not valid before berlin
+	transition := NewTransition(runtime.ForksInTime{}, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState))
+	_, err := transition.Write(msg)
+	assert.Equal(t, ErrTxTypeNotSupported, err)
+
+	transition = NewTransition(runtime.ForksInTime{Byzantium: true, Berlin: true}, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState))
+	result, err := transition.Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, TxGas+TxAccessListAddressGas+TxAccessListStorageKeyGas, result.GasUsed)
+}
+
+func TestAccessListWarmAccounts(t *testing.T) {
+	from := types.StringToAddress("a1")
+	to := types.StringToAddress("a2")
+
+	preState := map[types.Address]*PreState{
+		from: {
+			Balance: 1000000,
+		},
+	}
+
+	forks := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+	}
+
+	// BALANCE of the sender, of the blake2f precompile, of the recipient
+	// (ADDRESS) and of a cold account
+	code := append([]byte{0x73}, from.Bytes()...)
+	code = append(code, 0x31, 0x50, 0x60, 0x09, 0x31, 0x50, 0x30, 0x31, 0x50, 0x60, 0xa9, 0x31, 0x50, 0x00)
+
+	transition := NewTransition(forks, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState))
+	transition.Txn().SetCode(to, code)
+
+	result, err := transition.Write(&Transaction{
+		From:     from,
+		To:       &to,
+		Gas:      100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	// the sender, the recipient and the precompiles are warm (100), the
+	// other account is cold (2600)
+	warm := (3 + 100 + 2) + (3 + 100 + 2) + (2 + 100 + 2)
+	cold := 3 + 2600 + 2
+	assert.Equal(t, TxGas+uint64(warm+cold), result.GasUsed)
+}
+
+func TestDynamicFeeTransaction(t *testing.T) {
+	to := types.StringToAddress("a2")
+	coinbase := types.StringToAddress("c0")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	forks := runtime.ForksInTime{Byzantium: true, Berlin: true, London: true}
+	ctx := runtime.TxContext{
+		Coinbase: coinbase,
+		GasLimit: 100000,
+		BaseFee:  types.BytesToHash(big.NewInt(10).Bytes()),
+	}
+
+	msg := &Transaction{
+		Type:      DynamicFeeTx,
+		From:      addr1,
+		To:        &to,
+		Gas:       TxGas,
+		GasFeeCap: big.NewInt(15),
+		GasTipCap: big.NewInt(2),
+		Value:     big.NewInt(0),
+	}
+
+	// the sender pays base fee plus tip, the coinbase only receives the tip
+	txn := newStateWithPreState(preState)
+	transition := NewTransition(forks, ctx, txn)
+	result, err := transition.Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, big.NewInt(1000000-int64(TxGas)*12), transition.GetBalance(addr1))
+	assert.Equal(t, big.NewInt(int64(TxGas)*2), transition.GetBalance(coinbase))
+
+	// bor pays the base fee to the burnt contract
+	burnt := types.StringToAddress("b0")
+	forks.BurntContract = &burnt
+	transition = NewTransition(forks, ctx, newStateWithPreState(preState))
+	_, err = transition.Write(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(int64(TxGas)*10), transition.GetBalance(burnt))
+	assert.Equal(t, big.NewInt(int64(TxGas)*2), transition.GetBalance(coinbase))
+	forks.BurntContract = nil
+
+	// the fee cap must cover the base fee
+	msg.GasFeeCap = big.NewInt(9)
+	msg.GasTipCap = big.NewInt(1)
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.Equal(t, ErrFeeCapTooLow, err)
+
+	// the tip cannot be higher than the fee cap
+	msg.GasFeeCap = big.NewInt(15)
+	msg.GasTipCap = big.NewInt(16)
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.Equal(t, ErrTipAboveFeeCap, err)
+}
+
+func TestRefunds(t *testing.T) {
+	from := types.StringToAddress("a1")
+	to := types.StringToAddress("a2")
+
+	berlin := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+	}
+	london := berlin
+	london.London = true
+
+	// PUSH1 0 PUSH1 1 SSTORE
+	clearSlot1 := []byte{0x60, 0x00, 0x60, 0x01, 0x55}
+	// PUSH1 0 PUSH1 2 SSTORE
+	clearSlot2 := []byte{0x60, 0x00, 0x60, 0x02, 0x55}
+	// ADDRESS SELFDESTRUCT
+	selfdestruct := []byte{0x30, 0xff}
+
+	// each clear costs 5006 gas (two pushes and a cold sstore) and the
+	// selfdestruct 5002 gas
+	cases := []struct {
+		name    string
+		forks   runtime.ForksInTime
+		code    []byte
+		gasUsed uint64
+	}{
+		// the refund of 15000 is capped to half the gas used
+		{"clear berlin", berlin, clearSlot1, (21000 + 5006) - (21000+5006)/2},
+		// the refund of 4800 is below a fifth of the gas used (eip-3529)
+		{"clear london", london, clearSlot1, 21000 + 5006 - 4800},
+		// the refund of 2 * 4800 is capped to a fifth of the gas used
+		{"clear two london", london, append(append([]byte{}, clearSlot1...), clearSlot2...), (21000 + 2*5006) - (21000+2*5006)/5},
+		// the refund of 24000 is capped to half the gas used
+		{"selfdestruct berlin", berlin, selfdestruct, (21000 + 5002) - (21000+5002)/2},
+		// there is no selfdestruct refund after london (eip-3529)
+		{"selfdestruct london", london, selfdestruct, 21000 + 5002},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			preState := map[types.Address]*PreState{
+				from: {
+					Balance: 1000000,
+				},
+				to: {
+					State: map[types.Hash]types.Hash{
+						hash1: hash1,
+						hash2: hash2,
+					},
+				},
+			}
+
+			transition := NewTransition(c.forks, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState))
+			transition.Txn().SetCode(to, c.code)
+
+			result, err := transition.Write(&Transaction{
+				From:      from,
+				To:        &to,
+				Gas:       100000,
+				GasPrice:  big.NewInt(0),
+				GasFeeCap: big.NewInt(0),
+				GasTipCap: big.NewInt(0),
+				Value:     big.NewInt(0),
+			})
+			assert.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.Equal(t, c.gasUsed, result.GasUsed)
+		})
+	}
+}
+
+func TestCreateRejectsEFCode(t *testing.T) {
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	// init code that returns the single byte 0xEF
+	msg := &Transaction{
+		From:     addr1,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		Input:    []byte{0x60, 0xEF, 0x60, 0x00, 0x53, 0x60, 0x01, 0x60, 0x00, 0xF3},
+	}
+
+	forks := runtime.ForksInTime{Homestead: true, Byzantium: true, Berlin: true}
+
+	result, err := NewTransition(forks, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	forks.London = true
+
+	result, err = NewTransition(forks, runtime.TxContext{GasLimit: 100000}, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, msg.Gas, result.GasUsed)
+}
+
+func TestProcessWithdrawals(t *testing.T) {
+	addr := types.StringToAddress("a3")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1,
+		},
+	}
+
+	withdrawals := []*Withdrawal{
+		{Index: 0, ValidatorIndex: 1, Address: addr1, Amount: 2},
+		{Index: 1, ValidatorIndex: 2, Address: addr, Amount: 3},
+		{Index: 2, ValidatorIndex: 2, Address: addr, Amount: 4},
+	}
+
+	// withdrawals are not enabled before shanghai
+	transition := NewTransition(runtime.ForksInTime{London: true}, runtime.TxContext{}, newStateWithPreState(preState))
+	assert.Equal(t, ErrWithdrawalsNotEnabled, transition.ProcessWithdrawals(withdrawals))
+
+	transition = NewTransition(runtime.ForksInTime{London: true, Shanghai: true}, runtime.TxContext{}, newStateWithPreState(preState))
+	assert.NoError(t, transition.ProcessWithdrawals(withdrawals))
+
+	assert.Equal(t, big.NewInt(2000000001), transition.GetBalance(addr1))
+	assert.Equal(t, big.NewInt(7000000000), transition.GetBalance(addr))
+}
+
+func TestSelfdestructCancun(t *testing.T) {
+	contract := types.StringToAddress("a4")
+	beneficiary := types.StringToAddress("a5")
+
+	// PUSH20 beneficiary SELFDESTRUCT
+	code := append(append([]byte{0x73}, beneficiary.Bytes()...), 0xFF)
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+		contract: {
+			Balance: 10,
+		},
+	}
+
+	forks := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+		London: true, Shanghai: true, Cancun: true,
+	}
+
+	// a contract created in a previous transaction is not destroyed
+	transition := NewTransition(forks, runtime.TxContext{GasLimit: 1000000}, newStateWithPreState(preState))
+	transition.Txn().SetCode(contract, code)
+
+	result, err := transition.Write(&Transaction{
+		From:     addr1,
+		To:       &contract,
+		Gas:      100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, code, transition.GetCode(contract))
+	assert.Equal(t, 0, transition.GetBalance(contract).Sign())
+	assert.Equal(t, big.NewInt(10), transition.GetBalance(beneficiary))
+
+	// a contract created in the same transaction is destroyed
+	msg := &Transaction{
+		From:     addr1,
+		Gas:      100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(5),
+		Nonce:    1,
+		Input:    code,
+	}
+	result, err = transition.Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.False(t, transition.AccountExists(result.ContractAddress))
+	assert.Equal(t, big.NewInt(15), transition.GetBalance(beneficiary))
+}
+
+func TestBlobTransaction(t *testing.T) {
+	to := types.StringToAddress("a2")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000000,
+		},
+	}
+
+	forks := runtime.ForksInTime{Byzantium: true, Berlin: true, London: true, Shanghai: true, Cancun: true}
+	ctx := runtime.TxContext{
+		GasLimit: 100000,
+	}
+
+	blobHash := types.Hash{BlobTxHashVersion, 0x1}
+
+	newMsg := func() *Transaction {
+		return &Transaction{
+			Type:       BlobTx,
+			From:       addr1,
+			To:         &to,
+			Gas:        TxGas,
+			GasFeeCap:  big.NewInt(1),
+			GasTipCap:  big.NewInt(0),
+			Value:      big.NewInt(0),
+			BlobFeeCap: big.NewInt(1),
+			BlobHashes: []types.Hash{blobHash, blobHash},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		modify func(msg *Transaction)
+		err    error
+	}{
+		{"no recipient", func(msg *Transaction) { msg.To = nil }, ErrBlobTxCreate},
+		{"no blobs", func(msg *Transaction) { msg.BlobHashes = nil }, ErrMissingBlobHashes},
+		{"invalid version", func(msg *Transaction) { msg.BlobHashes = []types.Hash{{0x2}} }, ErrBlobInvalidVersion},
+		{"fee cap too low", func(msg *Transaction) { msg.BlobFeeCap = big.NewInt(0) }, ErrBlobFeeCapTooLow},
+		{"too many blobs", func(msg *Transaction) { msg.BlobHashes = make([]types.Hash, 7) }, ErrTooManyBlobs},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			msg := newMsg()
+			c.modify(msg)
+
+			_, err := NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+			assert.Equal(t, c.err, err)
+		})
+	}
+
+	// blob transactions are not valid before cancun
+	_, err := NewTransition(runtime.ForksInTime{London: true}, ctx, newStateWithPreState(preState)).Write(newMsg())
+	assert.Equal(t, ErrTxTypeNotSupported, err)
+
+	// the blob fee is burned with the minimum blob base fee. With an empty
+	// base fee and no tip the execution gas is free
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+	result, err := transition.Write(newMsg())
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	expected := 1000000000 - 2*int64(BlobTxBlobGasPerBlob)
+	assert.Equal(t, big.NewInt(expected), transition.GetBalance(addr1))
+}
+
+func TestCalcBlobFee(t *testing.T) {
+	cancun := runtime.CancunBlobConfig.UpdateFraction
+	prague := runtime.PragueBlobConfig.UpdateFraction
+
+	cases := []struct {
+		excessBlobGas  uint64
+		updateFraction uint64
+		blobFee        int64
+	}{
+		{0, cancun, 1},
+		{2314057, cancun, 1},
+		{2314058, cancun, 2},
+		{10 * 1024 * 1024, cancun, 23},
+		{2314058, prague, 1},
+		{3471086, prague, 1},
+		{3471087, prague, 2},
+		{10 * 1024 * 1024, prague, 8},
+	}
+	for _, c := range cases {
+		assert.Equal(t, big.NewInt(c.blobFee), CalcBlobFee(c.excessBlobGas, c.updateFraction))
+	}
+
+	// the blob base fee of the block uses the update fraction of the fork
+	ctx := runtime.TxContext{ExcessBlobGas: 10 * 1024 * 1024}
+
+	transition := NewTransition(runtime.ForksInTime{Cancun: true}, ctx, newStateWithPreState(nil))
+	assert.Equal(t, types.BytesToHash(big.NewInt(23).Bytes()), transition.GetTxContext().BlobBaseFee)
+
+	transition = NewTransition(runtime.ForksInTime{Cancun: true, Prague: true}, ctx, newStateWithPreState(nil))
+	assert.Equal(t, types.BytesToHash(big.NewInt(8).Bytes()), transition.GetTxContext().BlobBaseFee)
+
+	// and of the blob schedule of the chain
+	forks := runtime.ForksInTime{Cancun: true, Prague: true}
+	forks.Blobs = &runtime.BlobConfig{Target: 6, Max: 9, UpdateFraction: cancun}
+
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	assert.Equal(t, types.BytesToHash(big.NewInt(23).Bytes()), transition.GetTxContext().BlobBaseFee)
+}
+
+func TestProcessBeaconBlockRoot(t *testing.T) {
+	root := types.StringToHash("0x1234")
+
+	forks := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+		London: true, Shanghai: true, Cancun: true,
+	}
+	ctx := runtime.TxContext{GasLimit: 100000, ParentBeaconRoot: root}
+
+	// beacon block roots are not enabled before cancun
+	transition := NewTransition(runtime.ForksInTime{London: true}, ctx, newStateWithPreState(nil))
+	assert.Equal(t, ErrBeaconRootNotEnabled, transition.ProcessBeaconBlockRoot())
+
+	// noop without code in the contract
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	assert.NoError(t, transition.ProcessBeaconBlockRoot())
+	assert.False(t, transition.AccountExists(BeaconRootsAddress))
+
+	// PUSH1 0 CALLDATALOAD PUSH1 0 SSTORE
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	transition.Txn().SetCode(BeaconRootsAddress, []byte{0x60, 0x00, 0x35, 0x60, 0x00, 0x55})
+
+	assert.NoError(t, transition.ProcessBeaconBlockRoot())
+	assert.Equal(t, root, transition.GetStorage(BeaconRootsAddress, types.Hash{}))
+
+	// the system call does not use the gas pool or touch the system address
+	assert.Equal(t, uint64(100000), transition.gasPool)
+	assert.False(t, transition.AccountExists(SystemAddress))
+
+	// the system call does not see the blob hashes of the context and
+	// restores the context after the call
+	ctx.Origin = addr1
+	ctx.BlobHashes = []types.Hash{types.StringToHash("0x01")}
+
+	// PUSH1 0 BLOBHASH PUSH1 0 SSTORE
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	transition.Txn().SetCode(BeaconRootsAddress, []byte{0x60, 0x00, 0x49, 0x60, 0x00, 0x55})
+	transition.Txn().SetState(BeaconRootsAddress, types.Hash{}, root)
+
+	assert.NoError(t, transition.ProcessBeaconBlockRoot())
+	assert.Equal(t, types.Hash{}, transition.GetStorage(BeaconRootsAddress, types.Hash{}))
+	assert.Equal(t, addr1, transition.ctx.Origin)
+	assert.Equal(t, ctx.BlobHashes, transition.ctx.BlobHashes)
+}
+
+func TestAddSealingReward(t *testing.T) {
+	transition := NewTransition(runtime.ForksInTime{London: true}, runtime.TxContext{}, newStateWithPreState(nil))
+	assert.NoError(t, transition.AddSealingReward(addr1, big.NewInt(10)))
+	assert.Equal(t, big.NewInt(10), transition.GetBalance(addr1))
+
+	// there are no rewards after the merge
+	transition = NewTransition(runtime.ForksInTime{London: true, Paris: true}, runtime.TxContext{}, newStateWithPreState(nil))
+	assert.Equal(t, ErrRewardAfterMerge, transition.AddSealingReward(addr1, big.NewInt(10)))
+	assert.False(t, transition.AccountExists(addr1))
+
+	// a chain that keeps the rewards after paris disables eip-3675
+	forks := runtime.ForksInTime{London: true, Paris: true, EIPs: map[runtime.EIP]bool{runtime.EIP3675: false}}
+	transition = NewTransition(forks, runtime.TxContext{}, newStateWithPreState(nil))
+	assert.NoError(t, transition.AddSealingReward(addr1, big.NewInt(10)))
+	assert.Equal(t, big.NewInt(10), transition.GetBalance(addr1))
+}
+
+func signAuthorization(t *testing.T, key *btcec.PrivateKey, auth SetCodeAuthorization) SetCodeAuthorization {
+	hash := auth.SigHash()
+
+	sig, err := btcec.SignCompact(btcec.S256(), key, hash.Bytes(), false)
+	assert.NoError(t, err)
+
+	auth.V = sig[0] - 27
+	auth.R = new(big.Int).SetBytes(sig[1:33])
+	auth.S = new(big.Int).SetBytes(sig[33:65])
+	return auth
+}
+
+func TestSetCodeTransaction(t *testing.T) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	assert.NoError(t, err)
+
+	authority := helper.PubKeyToAddress(key.PubKey().ToECDSA())
+	target := types.StringToAddress("a6")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	forks := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+		London: true, Paris: true, Shanghai: true, Cancun: true, Prague: true,
+	}
+	ctx := runtime.TxContext{GasLimit: 1000000, ChainID: 1}
+
+	auth := signAuthorization(t, key, SetCodeAuthorization{
+		ChainID: big.NewInt(1),
+		Address: target,
+		Nonce:   0,
+	})
+
+	// the second authorization has a wrong nonce and it is skipped
+	invalidAuth := signAuthorization(t, key, SetCodeAuthorization{
+		ChainID: big.NewInt(0),
+		Address: types.StringToAddress("a7"),
+		Nonce:   0,
+	})
+
+	recovered, err := auth.Authority()
+	assert.NoError(t, err)
+	assert.Equal(t, authority, recovered)
+
+	msg := &Transaction{
+		Type:      SetCodeTx,
+		From:      addr1,
+		To:        &authority,
+		Gas:       100000,
+		GasFeeCap: big.NewInt(0),
+		GasTipCap: big.NewInt(0),
+		Value:     big.NewInt(0),
+		AuthList:  []SetCodeAuthorization{auth, invalidAuth},
+	}
+
+	// set code transactions are not valid before prague
+	forks.Prague = false
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.Equal(t, ErrTxTypeNotSupported, err)
+	forks.Prague = true
+
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+
+	// PUSH1 1 PUSH1 0 SSTORE
+	transition.Txn().SetCode(target, []byte{0x60, 0x01, 0x60, 0x00, 0x55})
+
+	result, err := transition.Write(msg)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+
+	// the authority delegates to the target and runs its code
+	assert.Equal(t, runtime.AddressToDelegation(target), transition.GetCode(authority))
+	assert.Equal(t, uint64(1), transition.GetNonce(authority))
+	assert.Equal(t, types.BytesToHash([]byte{0x1}), transition.GetStorage(authority, types.Hash{}))
+
+	// the transaction needs a recipient and authorizations
+	msg.Nonce = 1
+	msg.AuthList = nil
+	_, err = transition.Write(msg)
+	assert.Equal(t, ErrEmptyAuthList, err)
+}
+
+func TestSetCodeAuthorizationOutOfRange(t *testing.T) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	assert.NoError(t, err)
+
+	authority := helper.PubKeyToAddress(key.PubKey().ToECDSA())
+	auth := signAuthorization(t, key, SetCodeAuthorization{
+		ChainID: big.NewInt(1),
+		Address: types.StringToAddress("a6"),
+	})
+
+	overflow := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	cases := []struct {
+		auth func(a *SetCodeAuthorization)
+		err  error
+	}{
+		{func(a *SetCodeAuthorization) { a.ChainID = nil }, ErrAuthorizationInvalidChainID},
+		{func(a *SetCodeAuthorization) { a.ChainID = overflow }, ErrAuthorizationInvalidChainID},
+		{func(a *SetCodeAuthorization) { a.ChainID = big.NewInt(-1) }, ErrAuthorizationInvalidChainID},
+		{func(a *SetCodeAuthorization) { a.R = nil }, ErrAuthorizationInvalidSignature},
+		{func(a *SetCodeAuthorization) { a.S = nil }, ErrAuthorizationInvalidSignature},
+		{func(a *SetCodeAuthorization) { a.R = overflow }, ErrAuthorizationInvalidSignature},
+		{func(a *SetCodeAuthorization) { a.S = new(big.Int).Add(overflow, a.S) }, ErrAuthorizationInvalidSignature},
+	}
+
+	forks := runtime.ForksInTime{
+		Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+		Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+		London: true, Paris: true, Shanghai: true, Cancun: true, Prague: true,
+	}
+	ctx := runtime.TxContext{GasLimit: 1000000, ChainID: 1}
+
+	for _, c := range cases {
+		invalid := auth
+		c.auth(&invalid)
+
+		_, err := invalid.Authority()
+		assert.Equal(t, c.err, err)
+
+		// the invalid authorization is skipped
+		transition := NewTransition(forks, ctx, newStateWithPreState(map[types.Address]*PreState{addr1: {Balance: 1000000}}))
+		result, err := transition.Write(&Transaction{
+			Type:      SetCodeTx,
+			From:      addr1,
+			To:        &authority,
+			Gas:       100000,
+			GasFeeCap: big.NewInt(0),
+			GasTipCap: big.NewInt(0),
+			Value:     big.NewInt(0),
+			AuthList:  []SetCodeAuthorization{invalid},
+		})
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Empty(t, transition.GetCode(authority))
+		assert.Equal(t, uint64(0), transition.GetNonce(authority))
+	}
+}
+
+func TestTransactionGasCostAuthList(t *testing.T) {
+	msg := &Transaction{
+		To:       &addr2,
+		AuthList: []SetCodeAuthorization{{}, {}},
+	}
+
+	cost, err := TransactionGasCost(msg, true, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+2*TxAuthEmptyAccountGas, cost)
+}
+
+// historyStorageCode is the code of the history storage contract (eip-2935)
+var historyStorageCode = helper.MustDecodeHex("0x3373fffffffffffffffffffffffffffffffffffffffe14604657602036036042575f35600143038111604257611fff81430311604257611fff9006545f5260205ff35b5f5ffd5b5f35611fff60014303065500")
+
+func TestProcessParentBlockHash(t *testing.T) {
+	parent := types.StringToHash("0x1234")
+	ctx := runtime.TxContext{Number: 10000}
+
+	// history storage is not enabled before prague
+	transition := NewTransition(runtime.ForksInTime{Cancun: true}, ctx, newStateWithPreState(nil))
+	assert.Equal(t, ErrHistoryNotEnabled, transition.ProcessParentBlockHash(parent))
+
+	forks := runtime.ForksInTime{Homestead: true, Byzantium: true, Constantinople: true, Istanbul: true, Berlin: true, London: true, Shanghai: true, Cancun: true, Prague: true}
+
+	// noop without code in the contract
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	assert.NoError(t, transition.ProcessParentBlockHash(parent))
+	assert.False(t, transition.AccountExists(HistoryStorageAddress))
+
+	transition = NewTransition(forks, ctx, newStateWithPreState(nil))
+	transition.Txn().SetCode(HistoryStorageAddress, historyStorageCode)
+	assert.NoError(t, transition.ProcessParentBlockHash(parent))
+
+	// the contract stores the hash in the ring buffer
+	slot := types.BytesToHash(big.NewInt(9999 % HistoryServeWindow).Bytes())
+	assert.Equal(t, parent, transition.GetStorage(HistoryStorageAddress, slot))
+
+	// the block hashes are served from the state
+	assert.NotEqual(t, parent, transition.GetBlockHash(9999))
+	transition.SetGetHashFromState()
+	assert.Equal(t, parent, transition.GetBlockHash(9999))
+	assert.Equal(t, types.Hash{}, transition.GetBlockHash(9998))
+}
+
+func TestCalldataFloor(t *testing.T) {
+	to := types.StringToAddress("a2")
+	coinbase := types.StringToAddress("c0")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	input := make([]byte, 100)
+	for i := range input {
+		input[i] = 0x1
+	}
+
+	msg := &Transaction{
+		From:     addr1,
+		To:       &to,
+		Gas:      30000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+		Input:    input,
+	}
+
+	floor, err := FloorDataGas(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+400*TxCostFloorPerToken, floor)
+
+	ctx := runtime.TxContext{GasLimit: 100000, Coinbase: coinbase}
+
+	// before prague only the intrinsic gas is used
+	forks := runtime.ForksInTime{Homestead: true, Byzantium: true, Istanbul: true}
+	result, err := NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, TxGas+100*16, result.GasUsed)
+
+	forks.Prague = true
+
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+	result, err = transition.Write(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, floor, result.GasUsed)
+	assert.Equal(t, big.NewInt(int64(floor)), transition.GetBalance(coinbase))
+	assert.Equal(t, big.NewInt(1000000-int64(floor)), transition.GetBalance(addr1))
+
+	// the gas limit has to cover the floor. The rejected transaction takes
+	// neither the fee from the sender nor the gas from the block
+	msg.Gas = floor - 1
+	transition = NewTransition(forks, ctx, newStateWithPreState(preState))
+	_, err = transition.Write(msg)
+	assert.Equal(t, ErrFloorDataGas, err)
+	assert.Equal(t, big.NewInt(1000000), transition.GetBalance(addr1))
+	assert.Equal(t, uint64(100000), transition.gasPool)
+}
+
+func TestProcessRequests(t *testing.T) {
+	forks := runtime.ForksInTime{Byzantium: true, Berlin: true, London: true, Cancun: true, Prague: true}
+
+	// requests are not enabled before prague
+	transition := NewTransition(runtime.ForksInTime{Cancun: true}, runtime.TxContext{}, newStateWithPreState(nil))
+	_, err := transition.ProcessRequests()
+	assert.Equal(t, ErrRequestsNotEnabled, err)
+
+	// the request contracts must have code
+	transition = NewTransition(forks, runtime.TxContext{}, newStateWithPreState(nil))
+	_, err = transition.ProcessRequests()
+	assert.ErrorIs(t, err, ErrRequestsNoCode)
+
+	// abi encoded DepositEvent(pubkey, withdrawal_credentials, amount, signature, index)
+	deposit := []byte{}
+	logData := make([]byte, 576)
+	for i, field := range depositLogLayout {
+		offset, size := field[0], field[1]
+		copy(logData[i*32:], abiWord(offset))
+		copy(logData[offset:], abiWord(size))
+
+		value := bytes.Repeat([]byte{byte(i + 1)}, int(size))
+		copy(logData[offset+32:], value)
+		deposit = append(deposit, value...)
+	}
+
+	transition = NewTransition(forks, runtime.TxContext{}, newStateWithPreState(nil))
+	transition.receipts = []*Result{
+		{
+			Logs: []*Log{
+				// log from another contract
+				{Address: addr1, Topics: []types.Hash{depositEventTopic}, Data: logData},
+				{Address: DepositContractAddress, Topics: []types.Hash{depositEventTopic}, Data: logData},
+			},
+		},
+	}
+
+	// PUSH1 0x2a PUSH1 0 MSTORE PUSH1 0x20 PUSH1 0 RETURN
+	transition.Txn().SetCode(WithdrawalRequestAddress, []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3})
+	// STOP
+	transition.Txn().SetCode(ConsolidationRequestAddress, []byte{0x00})
+
+	requests, err := transition.ProcessRequests()
+	assert.NoError(t, err)
+	assert.Len(t, requests, 3)
+
+	withdrawal := types.BytesToHash([]byte{0x2a}).Bytes()
+
+	assert.Equal(t, &Request{Type: DepositRequestType, Data: deposit}, requests[0])
+	assert.Equal(t, &Request{Type: WithdrawalRequestType, Data: withdrawal}, requests[1])
+	assert.Equal(t, ConsolidationRequestType, requests[2].Type)
+	assert.Empty(t, requests[2].Data)
+
+	// the empty consolidation requests are not part of the commitment
+	depositHash := sha256.Sum256(append([]byte{0x0}, deposit...))
+	withdrawalHash := sha256.Sum256(append([]byte{0x1}, withdrawal...))
+	expected := sha256.Sum256(append(depositHash[:], withdrawalHash[:]...))
+
+	res := transition.BlockResult()
+	assert.Equal(t, requests, res.Requests)
+	assert.Equal(t, types.BytesToHash(expected[:]), res.RequestsHash)
+
+	// invalid deposit log layout
+	logData[31] = 0
+	transition.receipts[0].Logs[1].Data = logData
+	_, err = transition.ProcessRequests()
+	assert.Equal(t, ErrInvalidDepositLog, err)
+}
+
+func TestTransactionGasCap(t *testing.T) {
+	to := types.StringToAddress("a2")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000000,
+		},
+	}
+
+	msg := &Transaction{
+		From:     addr1,
+		To:       &to,
+		Gas:      MaxTxGas + 1,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	}
+
+	ctx := runtime.TxContext{GasLimit: 2 * int64(MaxTxGas)}
+	forks := runtime.ForksInTime{Homestead: true, Byzantium: true, Istanbul: true, Prague: true}
+
+	// the gas limit is not capped before osaka
+	_, err := NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+
+	forks.Osaka = true
+
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.Equal(t, ErrGasLimitTooHigh, err)
+
+	msg.Gas = MaxTxGas
+	_, err = NewTransition(forks, ctx, newStateWithPreState(preState)).Write(msg)
+	assert.NoError(t, err)
+}
+
+func TestSenderWithCode(t *testing.T) {
+	to := types.StringToAddress("a2")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000000,
+		},
+	}
+
+	msg := &Transaction{
+		From:     addr1,
+		To:       &to,
+		Gas:      TxGas,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	}
+
+	forks := runtime.ForksInTime{Homestead: true, Byzantium: true, Istanbul: true, Prague: true}
+	ctx := runtime.TxContext{GasLimit: 100000}
+
+	// the sender is a contract
+	transition := NewTransition(forks, ctx, newStateWithPreState(preState))
+	transition.Txn().SetCode(addr1, []byte{0x00})
+
+	_, err := transition.Write(msg)
+	assert.Equal(t, ErrSenderNoEOA, err)
+
+	// the check is disabled to simulate calls from contracts
+	transition.SetSkipSenderCodeCheck(true)
+	_, err = transition.Write(msg)
+	assert.NoError(t, err)
+
+	// accounts with a delegation can send transactions
+	transition = NewTransition(forks, ctx, newStateWithPreState(preState))
+	transition.Txn().SetCode(addr1, runtime.AddressToDelegation(addr2))
+
+	_, err = transition.Write(msg)
+	assert.NoError(t, err)
+}
+
+func TestIrregularChanges(t *testing.T) {
+	addr3 := types.StringToAddress("3")
+
+	preState := map[types.Address]*PreState{
+		addr1: {
+			Balance: 1000,
+		},
+		addr2: {
+			Balance: 500,
+		},
+	}
+
+	params := &runtime.Params{
+		Forks: &runtime.Forks{Homestead: runtime.NewFork(0)},
+		Irregular: []*runtime.IrregularChange{
+			{
+				Block: 10,
+				Moves: []*runtime.BalanceMove{
+					{From: []types.Address{addr1, addr2}, To: addr3},
+				},
+				Accounts: map[types.Address]*runtime.AccountOverride{
+					addr2: {
+						Code:    []byte{0x60, 0x01},
+						Balance: big.NewInt(7),
+						Storage: map[types.Hash]types.Hash{hash1: hash2},
+					},
+				},
+			},
+			{
+				Block: 10,
+				AtEnd: true,
+				Accounts: map[types.Address]*runtime.AccountOverride{
+					addr3: {
+						Code: []byte{0x60, 0x02},
+					},
+				},
+			},
+		},
+	}
+
+	// the changes only apply in their block
+	transition := NewTransition(params.At(9, 0), runtime.TxContext{Number: 9}, newStateWithPreState(preState))
+	transition.ProcessIrregular()
+	assert.Equal(t, big.NewInt(1000), transition.Txn().GetBalance(addr1))
+
+	// the transition does not apply the changes by itself
+	transition = NewTransition(params.At(10, 0), runtime.TxContext{Number: 10}, newStateWithPreState(preState))
+	txn := transition.Txn()
+	assert.Equal(t, big.NewInt(1000), txn.GetBalance(addr1))
+
+	transition.ProcessIrregular()
+	assert.Equal(t, 0, txn.GetBalance(addr1).Sign())
+	assert.Equal(t, big.NewInt(1500), txn.GetBalance(addr3))
+
+	// the accounts are replaced after the balances are moved
+	assert.Equal(t, big.NewInt(7), txn.GetBalance(addr2))
+	assert.Equal(t, []byte{0x60, 0x01}, txn.GetCode(addr2))
+	assert.Equal(t, hash2, txn.GetState(addr2, hash1))
+
+	// the changes at the end of the block are applied after the transactions
+	assert.Empty(t, txn.GetCode(addr3))
+
+	transition.ProcessIrregularEnd()
+	assert.Equal(t, []byte{0x60, 0x02}, txn.GetCode(addr3))
+	assert.Equal(t, big.NewInt(1500), txn.GetBalance(addr3))
+}
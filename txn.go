@@ -18,6 +18,18 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// the prefixes of the entries of a single transaction are hashes so
+	// that they are longer than the account keys and cannot match them
+
+	// accessListPrefix is the prefix of the access list entries (eip-2929) in the trie
+	accessListPrefix = helper.Keccak256([]byte("accessList"))
+
+	// transientStoragePrefix is the prefix of the transient storage entries (eip-1153) in the trie
+	transientStoragePrefix = helper.Keccak256([]byte("transientStorage"))
+
+	// createdContractPrefix is the prefix of the contracts created in the transaction (eip-6780) in the trie
+	createdContractPrefix = helper.Keccak256([]byte("createdContract"))
 )
 
 // Txn is a reference of the state
@@ -222,14 +234,20 @@ func (txn *Txn) SetStorage(addr types.Address, key types.Hash, value types.Hash,
 
 	txn.SetState(addr, key, value)
 
-	legacyGasMetering := !config.Istanbul && (config.Petersburg || !config.Constantinople)
+	clearRefund := uint64(15000)
+	if config.Enabled(runtime.EIP3529) {
+		// eip-3529
+		clearRefund = 4800
+	}
+
+	legacyGasMetering := !config.Enabled(runtime.EIP2200) && !config.Enabled(runtime.EIP1283)
 
 	if legacyGasMetering {
 		status = runtime.StorageModified
 		if oldValue == zeroHash {
 			return runtime.StorageAdded
 		} else if value == zeroHash {
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 			return runtime.StorageDeleted
 		}
 		return runtime.StorageModified
@@ -240,28 +258,34 @@ func (txn *Txn) SetStorage(addr types.Address, key types.Hash, value types.Hash,
 			return runtime.StorageAdded
 		}
 		if value == zeroHash { // delete slot (2.1.2b)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 			return runtime.StorageDeleted
 		}
 		return runtime.StorageModified
 	}
 	if original != zeroHash { // Storage slot was populated before this transaction started
 		if current == zeroHash { // recreate slot (2.2.1.1)
-			txn.SubRefund(15000)
+			txn.SubRefund(clearRefund)
 		} else if value == zeroHash { // delete slot (2.2.1.2)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearRefund)
 		}
 	}
 	if original == value {
 		if original == zeroHash { // reset to original nonexistent slot (2.2.2.1)
 			// Storage was used as memory (allocation and deallocation occurred within the same contract)
-			if config.Istanbul {
+			if config.Enabled(runtime.EIP2929) {
+				// eip-2929
+				txn.AddRefund(19900)
+			} else if config.Enabled(runtime.EIP2200) {
 				txn.AddRefund(19200)
 			} else {
 				txn.AddRefund(19800)
 			}
 		} else { // reset to original existing slot (2.2.2.2)
-			if config.Istanbul {
+			if config.Enabled(runtime.EIP2929) {
+				// eip-2929
+				txn.AddRefund(2800)
+			} else if config.Enabled(runtime.EIP2200) {
 				txn.AddRefund(4200)
 			} else {
 				txn.AddRefund(4800)
@@ -403,6 +427,74 @@ func (txn *Txn) SubRefund(gas uint64) {
 	txn.txn.Insert(refundIndex, refund)
 }
 
+// Access list
+
+func accessListKey(addr types.Address) []byte {
+	return append(append([]byte{}, accessListPrefix...), addr.Bytes()...)
+}
+
+func accessListSlotKey(addr types.Address, slot types.Hash) []byte {
+	return append(accessListKey(addr), slot.Bytes()...)
+}
+
+// AddressInAccessList returns true if the address has been accessed during the transaction
+func (txn *Txn) AddressInAccessList(addr types.Address) bool {
+	_, ok := txn.txn.Get(accessListKey(addr))
+	return ok
+}
+
+// SlotInAccessList returns whether the address and the slot have been accessed during the transaction
+func (txn *Txn) SlotInAccessList(addr types.Address, slot types.Hash) (addressOk bool, slotOk bool) {
+	_, addressOk = txn.txn.Get(accessListKey(addr))
+	_, slotOk = txn.txn.Get(accessListSlotKey(addr, slot))
+	return
+}
+
+// AddAddressToAccessList marks the address as accessed
+func (txn *Txn) AddAddressToAccessList(addr types.Address) {
+	txn.txn.Insert(accessListKey(addr), struct{}{})
+}
+
+// AddSlotToAccessList marks the address and the slot as accessed
+func (txn *Txn) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	txn.txn.Insert(accessListKey(addr), struct{}{})
+	txn.txn.Insert(accessListSlotKey(addr, slot), struct{}{})
+}
+
+func transientStorageKey(addr types.Address, key types.Hash) []byte {
+	return append(append(append([]byte{}, transientStoragePrefix...), addr.Bytes()...), key.Bytes()...)
+}
+
+// GetTransientStorage returns the transient storage value of the address for the key
+func (txn *Txn) GetTransientStorage(addr types.Address, key types.Hash) types.Hash {
+	val, ok := txn.txn.Get(transientStorageKey(addr, key))
+	if !ok {
+		return types.Hash{}
+	}
+	return val.(types.Hash)
+}
+
+// SetTransientStorage sets the transient storage value of the address for the key
+func (txn *Txn) SetTransientStorage(addr types.Address, key types.Hash, value types.Hash) {
+	txn.txn.Insert(transientStorageKey(addr, key), value)
+}
+
+func createdContractKey(addr types.Address) []byte {
+	return append(append([]byte{}, createdContractPrefix...), addr.Bytes()...)
+}
+
+// AddCreatedContract marks the address as created during the transaction.
+// The mark is part of the trie so it is only undone if the creation itself is reverted.
+func (txn *Txn) AddCreatedContract(addr types.Address) {
+	txn.txn.Insert(createdContractKey(addr), struct{}{})
+}
+
+// IsCreatedContract returns true if the address was created during the transaction
+func (txn *Txn) IsCreatedContract(addr types.Address) bool {
+	_, ok := txn.txn.Get(createdContractKey(addr))
+	return ok
+}
+
 func (txn *Txn) Logs() []*Log {
 	data, exists := txn.txn.Get(logIndex)
 	if !exists {
@@ -507,6 +599,15 @@ func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
 
 	// delete refunds
 	txn.txn.Delete(refundIndex)
+
+	// the access list is only valid for a single transaction
+	txn.txn.DeletePrefix(accessListPrefix)
+
+	// the transient storage is only valid for a single transaction
+	txn.txn.DeletePrefix(transientStoragePrefix)
+
+	// the created contracts are only tracked for a single transaction
+	txn.txn.DeletePrefix(createdContractPrefix)
 }
 
 func (txn *Txn) Commit() []*Object {
@@ -35,7 +35,8 @@ func (m *mockState) GetCode(hash types.Hash) ([]byte, bool) {
 }
 
 type mockSnapshot struct {
-	data map[string][]byte
+	data    map[string][]byte
+	storage map[types.Hash]map[types.Hash]types.Hash
 }
 
 func (m *mockSnapshot) NewSnapshotAt(types.Hash) (Snapshot, error) {
@@ -45,15 +46,30 @@ func (m *mockSnapshot) NewSnapshot() Snapshot {
 	panic("TODO")
 }
 func (m *mockSnapshot) GetCode(hash types.Hash) ([]byte, bool) {
-	panic("TODO")
+	// there is no code in the pre state
+	return nil, false
 }
 
 func (m *mockSnapshot) GetStorage(root types.Hash, key types.Hash) types.Hash {
+	if root == EmptyStateHash {
+		return types.Hash{}
+	}
+	if storage, ok := m.storage[root]; ok {
+		return storage[key]
+	}
 	panic("TODO")
 }
 
 func (m *mockSnapshot) GetAccount(addr types.Address) (*Account, error) {
-	panic("TODO")
+	data, ok := m.Get(hashit(addr.Bytes()))
+	if !ok {
+		return nil, nil
+	}
+	var account Account
+	if err := account.UnmarshalRlp(data); err != nil {
+		return nil, err
+	}
+	return &account, nil
 }
 
 func (m *mockSnapshot) Get(k []byte) ([]byte, bool) {
@@ -70,7 +86,8 @@ func newStateWithPreState(preState map[types.Address]*PreState) *mockSnapshot {
 		snapshots: map[types.Hash]Snapshot{},
 	}
 	snapshot := &mockSnapshot{
-		data: map[string][]byte{},
+		data:    map[string][]byte{},
+		storage: map[types.Hash]map[types.Hash]types.Hash{},
 	}
 
 	ar := &fastrlp.Arena{}
@@ -78,6 +95,7 @@ func newStateWithPreState(preState map[types.Address]*PreState) *mockSnapshot {
 		account, snap := buildMockPreState(p)
 		if snap != nil {
 			state.snapshots[account.Root] = snap
+			snapshot.storage[account.Root] = p.State
 		}
 
 		v := account.MarshalWith(ar)
@@ -147,6 +165,68 @@ func TestSnapshotUpdateData(t *testing.T) {
 	assert.Equal(t, hash1, txn.GetState(addr1, hash1))
 }
 
+func TestSnapshotAccessList(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	txn.AddAddressToAccessList(addr1)
+	assert.True(t, txn.AddressInAccessList(addr1))
+
+	ss := txn.Snapshot()
+	txn.AddSlotToAccessList(addr2, hash1)
+
+	addrOk, slotOk := txn.SlotInAccessList(addr2, hash1)
+	assert.True(t, addrOk)
+	assert.True(t, slotOk)
+
+	txn.RevertToSnapshot(ss)
+
+	addrOk, slotOk = txn.SlotInAccessList(addr2, hash1)
+	assert.False(t, addrOk)
+	assert.False(t, slotOk)
+	assert.True(t, txn.AddressInAccessList(addr1))
+
+	// the access list does not survive the transaction
+	txn.CleanDeleteObjects(true)
+	assert.False(t, txn.AddressInAccessList(addr1))
+}
+
+func TestSnapshotTransientStorage(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	txn.SetTransientStorage(addr1, hash1, hash1)
+	assert.Equal(t, hash1, txn.GetTransientStorage(addr1, hash1))
+
+	ss := txn.Snapshot()
+	txn.SetTransientStorage(addr1, hash1, hash2)
+	txn.SetTransientStorage(addr2, hash1, hash2)
+	assert.Equal(t, hash2, txn.GetTransientStorage(addr1, hash1))
+
+	txn.RevertToSnapshot(ss)
+	assert.Equal(t, hash1, txn.GetTransientStorage(addr1, hash1))
+	assert.Equal(t, types.Hash{}, txn.GetTransientStorage(addr2, hash1))
+
+	// the transient storage does not survive the transaction
+	txn.CleanDeleteObjects(true)
+	assert.Equal(t, types.Hash{}, txn.GetTransientStorage(addr1, hash1))
+}
+
+func TestCleanDeleteObjectsKeepsAccounts(t *testing.T) {
+	// an account whose address starts with the bytes of a former prefix
+	addr := types.BytesToAddress([]byte("accessList0123456789"))
+
+	txn := newTestTxn(defaultPreState)
+	txn.SetBalance(addr, big.NewInt(1))
+	txn.AddAddressToAccessList(addr)
+	txn.SetTransientStorage(addr, hash1, hash1)
+	txn.AddCreatedContract(addr)
+
+	txn.CleanDeleteObjects(true)
+	assert.Equal(t, big.NewInt(1), txn.GetBalance(addr))
+	assert.False(t, txn.AddressInAccessList(addr))
+	assert.Equal(t, types.Hash{}, txn.GetTransientStorage(addr, hash1))
+	assert.False(t, txn.IsCreatedContract(addr))
+}
+
 func hashit(k []byte) []byte {
 	h := sha3.NewLegacyKeccak256()
 	h.Write(k)
@@ -1,9 +1,12 @@
 package state
 
 import (
+	"crypto/sha256"
 	"math/big"
 
+	"github.com/0xPolygon/eth-state-transition/helper"
 	"github.com/0xPolygon/eth-state-transition/types"
+	"github.com/umbracle/fastrlp"
 )
 
 var (
@@ -11,32 +14,254 @@ var (
 	EmptyRootHash = types.StringToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
 )
 
+// TxType is the type of the transaction (eip-2718)
+type TxType byte
+
+const (
+	LegacyTx     TxType = 0x0
+	AccessListTx TxType = 0x1
+	DynamicFeeTx TxType = 0x2
+	BlobTx       TxType = 0x3
+	SetCodeTx    TxType = 0x4
+)
+
+// AccessTuple is an address and the storage keys it accesses (eip-2930)
+type AccessTuple struct {
+	Address     types.Address
+	StorageKeys []types.Hash
+}
+
+// AccessList is the list of addresses and storage keys a transaction plans to access
+type AccessList []AccessTuple
+
+// StorageKeys returns the total number of storage keys in the access list
+func (a AccessList) StorageKeys() int {
+	num := 0
+	for _, tuple := range a {
+		num += len(tuple.StorageKeys)
+	}
+	return num
+}
+
+func (a AccessList) Copy() AccessList {
+	if a == nil {
+		return nil
+	}
+	aa := make(AccessList, len(a))
+	for i, tuple := range a {
+		aa[i] = AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]types.Hash{}, tuple.StorageKeys...),
+		}
+	}
+	return aa
+}
+
 type Transaction struct {
-	Nonce    uint64
-	GasPrice *big.Int
-	Gas      uint64
-	To       *types.Address
-	Value    *big.Int
-	Input    []byte
-	Hash     types.Hash
-	From     types.Address
+	Type       TxType
+	Nonce      uint64
+	GasPrice   *big.Int
+	GasFeeCap  *big.Int
+	GasTipCap  *big.Int
+	Gas        uint64
+	To         *types.Address
+	Value      *big.Int
+	Input      []byte
+	AccessList AccessList
+	BlobFeeCap *big.Int
+	BlobHashes []types.Hash
+	AuthList   []SetCodeAuthorization
+	Hash       types.Hash
+	From       types.Address
 }
 
 func (t *Transaction) IsContractCreation() bool {
 	return t.To == nil
 }
 
+// GetGasFeeCap returns the max fee per gas the sender is willing to pay.
+// It is the gas price for the transactions without dynamic fees.
+func (t *Transaction) GetGasFeeCap() *big.Int {
+	if t.GasFeeCap != nil {
+		return t.GasFeeCap
+	}
+	return t.GasPrice
+}
+
+// GetGasTipCap returns the max priority fee per gas the sender is willing to pay.
+// It is the gas price for the transactions without dynamic fees.
+func (t *Transaction) GetGasTipCap() *big.Int {
+	if t.GasTipCap != nil {
+		return t.GasTipCap
+	}
+	return t.GasPrice
+}
+
+// EffectiveGasPrice returns the price paid per unit of gas
+// given the base fee of the block (eip-1559)
+func (t *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	feeCap := t.GetGasFeeCap()
+
+	price := new(big.Int).Add(t.GetGasTipCap(), baseFee)
+	if price.Cmp(feeCap) > 0 {
+		price.Set(feeCap)
+	}
+	return price
+}
+
+// BlobGas returns the blob gas used by the transaction (eip-4844)
+func (t *Transaction) BlobGas() uint64 {
+	return uint64(len(t.BlobHashes)) * BlobTxBlobGasPerBlob
+}
+
+func copyBigInt(b *big.Int) *big.Int {
+	if b == nil {
+		return nil
+	}
+	return new(big.Int).Set(b)
+}
+
 func (t *Transaction) Copy() *Transaction {
 	tt := new(Transaction)
 	*tt = *t
 
-	tt.GasPrice = new(big.Int)
-	tt.GasPrice.Set(t.GasPrice)
+	tt.GasPrice = copyBigInt(t.GasPrice)
+	tt.GasFeeCap = copyBigInt(t.GasFeeCap)
+	tt.GasTipCap = copyBigInt(t.GasTipCap)
 
 	tt.Value = new(big.Int)
 	tt.Value.Set(t.Value)
 
 	tt.Input = make([]byte, len(t.Input))
 	copy(tt.Input[:], t.Input[:])
+
+	tt.AccessList = t.AccessList.Copy()
+
+	tt.BlobFeeCap = copyBigInt(t.BlobFeeCap)
+	if t.BlobHashes != nil {
+		tt.BlobHashes = append([]types.Hash{}, t.BlobHashes...)
+	}
+	if t.AuthList != nil {
+		tt.AuthList = append([]SetCodeAuthorization{}, t.AuthList...)
+	}
 	return tt
 }
+
+// Withdrawal is a validator withdrawal from the consensus layer (eip-4895)
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        types.Address
+	Amount         uint64 // in Gwei
+}
+
+// AmountWei returns the amount of the withdrawal in wei
+func (w *Withdrawal) AmountWei() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(w.Amount), big.NewInt(1e9))
+}
+
+// RequestType is the type of an execution layer request (eip-7685)
+type RequestType byte
+
+const (
+	DepositRequestType       RequestType = 0x0
+	WithdrawalRequestType    RequestType = 0x1
+	ConsolidationRequestType RequestType = 0x2
+)
+
+// Request is the list of execution layer requests of one type in
+// a block (eip-7685). Data is the concatenation of the requests.
+type Request struct {
+	Type RequestType
+	Data []byte
+}
+
+// Bytes returns the request type followed by the request data
+func (r *Request) Bytes() []byte {
+	return append([]byte{byte(r.Type)}, r.Data...)
+}
+
+// RequestsHash returns the commitment to the requests of a block (eip-7685).
+// Requests with empty data are not part of the commitment.
+func RequestsHash(requests []*Request) types.Hash {
+	h := sha256.New()
+	for _, r := range requests {
+		if len(r.Data) == 0 {
+			continue
+		}
+		hash := sha256.Sum256(r.Bytes())
+		h.Write(hash[:])
+	}
+	return types.BytesToHash(h.Sum(nil))
+}
+
+// SetCodeAuthorization is a signed authorization of an account
+// to delegate its code to another address (eip-7702)
+type SetCodeAuthorization struct {
+	ChainID *big.Int
+	Address types.Address
+	Nonce   uint64
+	V       byte
+	R       *big.Int
+	S       *big.Int
+}
+
+// setCodeMagic is the prefix of the signed message of the authorizations (eip-7702)
+const setCodeMagic = 0x05
+
+var (
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1halfN = new(big.Int).Div(secp256k1N, big.NewInt(2))
+)
+
+// isUint256 returns whether the value is set and fits in 256 bits
+func isUint256(v *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.BitLen() <= 256
+}
+
+// SigHash returns the hash signed by the authority. A nil chain id is
+// hashed as zero, Authority rejects it.
+func (a *SetCodeAuthorization) SigHash() types.Hash {
+	chainID := a.ChainID
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+
+	ar := &fastrlp.Arena{}
+
+	v := ar.NewArray()
+	v.Set(ar.NewBigInt(chainID))
+	v.Set(ar.NewBytes(a.Address.Bytes()))
+	v.Set(ar.NewUint(a.Nonce))
+
+	return types.BytesToHash(helper.Keccak256([]byte{setCodeMagic}, v.MarshalTo(nil)))
+}
+
+// Authority recovers the address that signed the authorization. The chain
+// id and the signature values must fit in 256 bits (eip-7702).
+func (a *SetCodeAuthorization) Authority() (types.Address, error) {
+	if !isUint256(a.ChainID) {
+		return types.Address{}, ErrAuthorizationInvalidChainID
+	}
+	if !isUint256(a.R) || !isUint256(a.S) {
+		return types.Address{}, ErrAuthorizationInvalidSignature
+	}
+
+	r, s := make([]byte, 32), make([]byte, 32)
+	a.R.FillBytes(r)
+	a.S.FillBytes(s)
+
+	// only signatures in the lower half of the curve are valid
+	if !helper.ValidateSignatureValues(a.V, r, s) || a.S.Cmp(secp256k1halfN) > 0 {
+		return types.Address{}, ErrAuthorizationInvalidSignature
+	}
+
+	sig := append(append(r, s...), a.V)
+	hash := a.SigHash()
+
+	pub, err := helper.Ecrecover(hash.Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return types.BytesToAddress(helper.Keccak256(pub[1:])[12:]), nil
+}